@@ -0,0 +1,104 @@
+package lime
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Args provides typed access to the positional args given to a Func. Since it is a plain []string, the args can be
+// wrapped by conversion: `lime.Args(args)`.
+// Positions are zero-based, but are reported counting from 1 in the returned errors.
+type Args []string
+
+// Len returns the number of args
+func (a Args) Len() int {
+	return len(a)
+}
+
+// String returns the arg at position i. Returns an error if there is no such arg.
+func (a Args) String(i int) (string, error) {
+	if i < 0 || i >= len(a) {
+		return "", &ArgumentError{Position: i, Reason: "a value is required"}
+	}
+	return a[i], nil
+}
+
+// StringOr returns the arg at position i, or def if there is no such arg
+func (a Args) StringOr(i int, def string) string {
+	if i < 0 || i >= len(a) {
+		return def
+	}
+	return a[i]
+}
+
+// Int returns the arg at position i as an int. Returns an error if there is no such arg or it is not an integer.
+func (a Args) Int(i int) (int, error) {
+	s, err := a.String(i)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, &ArgumentError{Position: i, Reason: fmt.Sprintf("%q is not an integer", s)}
+	}
+	return n, nil
+}
+
+// IntOr returns the arg at position i as an int, or def if there is no such arg.
+// Returns an error if the arg is present but is not an integer.
+func (a Args) IntOr(i int, def int) (int, error) {
+	if i < 0 || i >= len(a) {
+		return def, nil
+	}
+	return a.Int(i)
+}
+
+// Float64 returns the arg at position i as a float64. Returns an error if there is no such arg or it is not a number.
+func (a Args) Float64(i int) (float64, error) {
+	s, err := a.String(i)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, &ArgumentError{Position: i, Reason: fmt.Sprintf("%q is not a number", s)}
+	}
+	return f, nil
+}
+
+// Bool returns the arg at position i as a bool, accepting the values understood by strconv.ParseBool.
+// Returns an error if there is no such arg or it is not a boolean.
+func (a Args) Bool(i int) (bool, error) {
+	s, err := a.String(i)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, &ArgumentError{Position: i, Reason: fmt.Sprintf("%q is not a boolean", s)}
+	}
+	return b, nil
+}
+
+// Duration returns the arg at position i as a time.Duration, such as `1m30s`.
+// Returns an error if there is no such arg or it is not a duration.
+func (a Args) Duration(i int) (time.Duration, error) {
+	s, err := a.String(i)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, &ArgumentError{Position: i, Reason: fmt.Sprintf("%q is not a duration", s)}
+	}
+	return d, nil
+}
+
+// Rest returns the args from position i onward. Returns an empty slice if there are no such args.
+func (a Args) Rest(i int) []string {
+	if i < 0 || i >= len(a) {
+		return []string{}
+	}
+	return a[i:]
+}
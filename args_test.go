@@ -0,0 +1,63 @@
+package lime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArgs(t *testing.T) {
+	args := Args{"42", "1m30s", "words", "and", "more", "nope"}
+
+	if args.Len() != 6 {
+		t.Error("the `Len` method did not count the args")
+	}
+
+	if n, err := args.Int(0); err != nil || n != 42 {
+		t.Errorf("the `Int` method did not parse the arg, got %d, %v", n, err)
+	}
+
+	if d, err := args.Duration(1); err != nil || d != 90*time.Second {
+		t.Errorf("the `Duration` method did not parse the arg, got %s, %v", d, err)
+	}
+
+	if s := args.StringOr(2, "default"); s != "words" {
+		t.Errorf("the `StringOr` method did not return the arg, got %s", s)
+	}
+
+	if s := args.StringOr(6, "default"); s != "default" {
+		t.Errorf("the `StringOr` method did not return the default for a missing arg, got %s", s)
+	}
+
+	if n, err := args.IntOr(6, 7); err != nil || n != 7 {
+		t.Errorf("the `IntOr` method did not return the default for a missing arg, got %d, %v", n, err)
+	}
+
+	if rest := args.Rest(3); len(rest) != 3 || rest[0] != "and" {
+		t.Errorf("the `Rest` method did not return the remaining args, got %v", rest)
+	}
+
+	if rest := args.Rest(10); len(rest) != 0 {
+		t.Errorf("the `Rest` method returned args past the end, got %v", rest)
+	}
+}
+
+func TestArgs_Errors(t *testing.T) {
+	args := Args{"42", "nope"}
+
+	cases := []struct {
+		err    error
+		expect string
+	}{
+		{func() error { _, err := args.Int(1); return err }(), `argument 2: "nope" is not an integer`},
+		{func() error { _, err := args.Duration(1); return err }(), `argument 2: "nope" is not a duration`},
+		{func() error { _, err := args.Bool(1); return err }(), `argument 2: "nope" is not a boolean`},
+		{func() error { _, err := args.Float64(2); return err }(), `argument 3: a value is required`},
+		{func() error { _, err := args.IntOr(1, 0); return err }(), `argument 2: "nope" is not an integer`},
+	}
+
+	for _, tc := range cases {
+		if tc.err == nil || tc.err.Error() != tc.expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", tc.expect, tc.err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package lime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArgumentType determines which values are accepted by an Argument
+type ArgumentType int
+
+const (
+	// StringArgument accepts any value
+	StringArgument ArgumentType = iota
+	// IntArgument accepts only integers
+	IntArgument
+	// PathArgument accepts only paths to existing files or directories
+	PathArgument
+	// EnumArgument accepts only the values listed in the Argument's Enum field
+	EnumArgument
+)
+
+// String returns the name of the type, such as "int"
+func (t ArgumentType) String() string {
+	switch t {
+	case IntArgument:
+		return "int"
+	case PathArgument:
+		return "path"
+	case EnumArgument:
+		return "enum"
+	}
+	return "string"
+}
+
+// Argument defines a positional argument accepted by a Command
+type Argument struct {
+	// The name of the argument, used in synopsis lines and error messages
+	Name string
+	// A brief description of the argument, used in command-specific --help output
+	Description string
+	// Whether the argument may be left out
+	Optional bool
+	// Whether the argument accepts all remaining values. Only the last Argument of a Command may be variadic
+	Variadic bool
+	// The type of value accepted by the argument
+	Type ArgumentType
+	// The accepted values when Type is EnumArgument
+	Enum []string
+}
+
+// Describe returns the description of the argument, along with the values it accepts if it is an enum
+func (a Argument) Describe() string {
+	if a.Type == EnumArgument {
+		return fmt.Sprintf("%s (one of: %s)", a.Description, strings.Join(a.Enum, ", "))
+	}
+	return a.Description
+}
+
+// Synopsis returns the argument as it appears in a synopsis line, such as `<word>...` or `[name]`
+func (a Argument) Synopsis() string {
+	s := "<" + a.Name + ">"
+	if a.Optional {
+		s = "[" + a.Name + "]"
+	}
+	if a.Variadic {
+		s += "..."
+	}
+	return s
+}
+
+// ArgumentError describes a problem with a single positional argument
+type ArgumentError struct {
+	// The zero-based position of the argument
+	Position int
+	// The name of the argument, if it has one
+	Name string
+	// What is wrong with the argument
+	Reason string
+}
+
+// Error implements the error interface. Positions are reported counting from 1.
+func (e *ArgumentError) Error() string {
+	if len(e.Name) > 0 {
+		return fmt.Sprintf("argument %d (%s): %s", e.Position+1, e.Name, e.Reason)
+	}
+	return fmt.Sprintf("argument %d: %s", e.Position+1, e.Reason)
+}
@@ -0,0 +1,229 @@
+package lime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	writerType   = reflect.TypeOf((*io.Writer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// binding connects a struct field to the flag or positional argument which populates it
+type binding struct {
+	field    int
+	flag     string
+	position int
+	variadic bool
+	def      string
+}
+
+// Bind returns a copy of c which populates a struct from its args and flags, then invokes fn with it.
+// fn must have the signature `func(context.Context, *T, io.Writer) error`, where T is a struct whose fields are bound
+// with these tags:
+//
+//	flag:"name"      binds the field to the flag --name
+//	arg:"0"          binds the field to the positional argument at the given position
+//	help:"..."       describes the flag or argument in --help output
+//	default:"..."    the value to use when the flag or argument is not given
+//	required:"true"  makes the flag or argument required
+//	enum:"a,b"       limits the values accepted by an argument
+//
+// Bound fields may be a string, bool, int, int64, float64 or time.Duration. A []string field bound to the last
+// argument is variadic. The Arguments and Flags of c are replaced by the ones derived from T.
+// Bind panics if fn or T do not follow these rules, since that is a mistake in the program rather than in its input.
+func Bind(c Command, fn interface{}) Command {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 3 || ft.NumOut() != 1 ||
+		ft.In(0) != contextType || ft.In(1).Kind() != reflect.Ptr || ft.In(2) != writerType || ft.Out(0) != errorType {
+		panic(fmt.Sprintf("lime: Bind for %q needs a func(context.Context, *T, io.Writer) error, got %s", c.Keyword, ft))
+	}
+
+	t := ft.In(1).Elem()
+	bs, arguments, flags, err := bindings(t)
+	if err != nil {
+		panic(fmt.Sprintf("lime: Bind for %q: %s", c.Keyword, err))
+	}
+
+	c.Arguments = arguments
+	c.Flags = flags
+	c.ContextFunc = func(ctx context.Context, args []string, out io.Writer) error {
+		v := reflect.New(t)
+		if err := populate(v.Elem(), bs, args, FlagsFrom(ctx)); err != nil {
+			return err
+		}
+		res := fv.Call([]reflect.Value{reflect.ValueOf(&ctx).Elem(), v, reflect.ValueOf(&out).Elem()})
+		err, _ := res[0].Interface().(error)
+		return err
+	}
+
+	return c
+}
+
+// bindings reads the struct tags of t, returning the bindings of its fields along with the Arguments and Flags
+// they declare
+func bindings(t reflect.Type) ([]binding, []Argument, []Flag, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil, nil, fmt.Errorf("%s is not a struct", t)
+	}
+
+	bs := make([]binding, 0, t.NumField())
+	arguments := make([]Argument, 0)
+	flags := make([]Flag, 0)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		flagName, isFlag := sf.Tag.Lookup("flag")
+		pos, isArg := sf.Tag.Lookup("arg")
+		if !isFlag && !isArg {
+			continue
+		}
+		if isFlag && isArg {
+			return nil, nil, nil, fmt.Errorf("field %s has both a flag and an arg tag", sf.Name)
+		}
+		if len(sf.PkgPath) > 0 {
+			return nil, nil, nil, fmt.Errorf("field %s is unexported, so it cannot be populated", sf.Name)
+		}
+		if !bindable(sf.Type) {
+			return nil, nil, nil, fmt.Errorf("field %s has unsupported type %s", sf.Name, sf.Type)
+		}
+
+		b := binding{field: i, flag: flagName, def: sf.Tag.Get("default")}
+		required := sf.Tag.Get("required") == "true"
+
+		if isFlag {
+			if sf.Type.Kind() == reflect.Slice {
+				return nil, nil, nil, fmt.Errorf("flag field %s cannot be a slice", sf.Name)
+			}
+			flags = append(flags, Flag{
+				Name:        flagName,
+				Description: sf.Tag.Get("help"),
+				Default:     b.def,
+				Required:    required,
+				Bool:        sf.Type.Kind() == reflect.Bool,
+			})
+			bs = append(bs, b)
+			continue
+		}
+
+		n, err := strconv.Atoi(pos)
+		if err != nil || n != len(arguments) {
+			return nil, nil, nil, fmt.Errorf("field %s must be bound to argument %d, got %q", sf.Name, len(arguments), pos)
+		}
+		if len(arguments) > 0 && arguments[len(arguments)-1].Variadic {
+			return nil, nil, nil, fmt.Errorf("field %s follows a variadic argument", sf.Name)
+		}
+		b.position = n
+		b.variadic = sf.Type.Kind() == reflect.Slice
+
+		a := Argument{
+			Name:        strings.ToLower(sf.Name),
+			Description: sf.Tag.Get("help"),
+			Optional:    !required,
+			Variadic:    b.variadic,
+		}
+		if enum, ok := sf.Tag.Lookup("enum"); ok {
+			a.Type = EnumArgument
+			a.Enum = strings.Split(enum, ",")
+		} else if sf.Type.Kind() == reflect.Int || (sf.Type.Kind() == reflect.Int64 && sf.Type != durationType) {
+			a.Type = IntArgument
+		}
+		arguments = append(arguments, a)
+		bs = append(bs, b)
+	}
+
+	return bs, arguments, flags, nil
+}
+
+// bindable returns true if a struct field of type t can be populated by Bind
+func bindable(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// populate sets the fields of the struct v from the given args and flags
+func populate(v reflect.Value, bs []binding, args Args, flags Flags) error {
+	for _, b := range bs {
+		fv := v.Field(b.field)
+
+		if b.variadic {
+			rest := args.Rest(b.position)
+			if len(rest) == 0 && len(b.def) > 0 {
+				rest = strings.Split(b.def, ",")
+			}
+			fv.Set(reflect.ValueOf(append([]string{}, rest...)))
+			continue
+		}
+
+		var raw string
+		if len(b.flag) > 0 {
+			raw = flags[b.flag]
+		} else {
+			raw = args.StringOr(b.position, "")
+		}
+		if len(raw) == 0 {
+			raw = b.def
+		}
+		if len(raw) == 0 {
+			continue
+		}
+
+		if err := set(fv, raw); err != nil {
+			if len(b.flag) > 0 {
+				return &FlagError{Name: b.flag, Reason: err.Reason}
+			}
+			err.Position = b.position
+			err.Name = strings.ToLower(v.Type().Field(b.field).Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set converts raw to the type of fv and stores it, reusing the conversions of Args
+func set(fv reflect.Value, raw string) *ArgumentError {
+	a := Args{raw}
+	var err error
+	switch {
+	case fv.Type() == durationType:
+		var d time.Duration
+		d, err = a.Duration(0)
+		fv.SetInt(int64(d))
+	case fv.Kind() == reflect.String:
+		fv.SetString(raw)
+	case fv.Kind() == reflect.Bool:
+		var b bool
+		b, err = a.Bool(0)
+		fv.SetBool(b)
+	case fv.Kind() == reflect.Int || fv.Kind() == reflect.Int64:
+		var n int
+		n, err = a.Int(0)
+		fv.SetInt(int64(n))
+	case fv.Kind() == reflect.Float64:
+		var f float64
+		f, err = a.Float64(0)
+		fv.SetFloat(f)
+	}
+
+	if err != nil {
+		return err.(*ArgumentError)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package lime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type deployOptions struct {
+	Env     string        `flag:"env" help:"The environment to deploy to" default:"staging"`
+	DryRun  bool          `flag:"dry-run" help:"Only print the plan"`
+	Timeout time.Duration `flag:"timeout" default:"30s"`
+	Target  string        `arg:"0" help:"What to deploy" required:"true"`
+	Count   int           `arg:"1" default:"1"`
+	Tags    []string      `arg:"2"`
+	ignored string
+}
+
+func deploy(_ context.Context, o *deployOptions, out io.Writer) error {
+	_, _ = fmt.Fprintf(out, "%s %s %t %s %d %v", o.Target, o.Env, o.DryRun, o.Timeout, o.Count, o.Tags)
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	c := Bind(Command{Keyword: "deploy"}, deploy)
+
+	// Ensure the flags and arguments are derived from the struct tags
+	{
+		if len(c.Flags) != 3 || c.Flags[0].Name != "env" || c.Flags[0].Default != "staging" || !c.Flags[1].Bool {
+			t.Errorf("the `Bind` function did not derive the flags, got %+v", c.Flags)
+		}
+
+		if len(c.Arguments) != 3 || c.Arguments[0].Optional || c.Arguments[1].Type != IntArgument || !c.Arguments[2].Variadic {
+			t.Errorf("the `Bind` function did not derive the arguments, got %+v", c.Arguments)
+		}
+	}
+
+	// Ensure the struct is populated from the args and flags
+	{
+		sb := new(strings.Builder)
+		ctx := WithFlags(context.Background(), Flags{"env": "prod", "dry-run": "true", "timeout": "1m"})
+		err := c.ContextFunc(ctx, []string{"api", "3", "a", "b"}, sb)
+
+		if err != nil {
+			t.Errorf("the bound function returned an error: %s", err)
+		}
+
+		if expect := "api prod true 1m0s 3 [a b]"; sb.String() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, sb.String())
+		}
+	}
+
+	// Ensure defaults are used for missing values
+	{
+		sb := new(strings.Builder)
+		err := c.ContextFunc(context.Background(), []string{"api"}, sb)
+
+		if err != nil {
+			t.Errorf("the bound function returned an error: %s", err)
+		}
+
+		if expect := "api staging false 30s 1 []"; sb.String() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, sb.String())
+		}
+	}
+
+	// Ensure conversion errors mention the argument or flag
+	{
+		err := c.ContextFunc(context.Background(), []string{"api", "three"}, io.Discard)
+		if expect := `argument 2 (count): "three" is not an integer`; err == nil || err.Error() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+		}
+
+		ctx := WithFlags(context.Background(), Flags{"timeout": "soon"})
+		err = c.ContextFunc(ctx, []string{"api"}, io.Discard)
+		if expect := `flag --timeout: "soon" is not a duration`; err == nil || err.Error() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+		}
+	}
+}
+
+func TestBind_Invalid(t *testing.T) {
+	type unsupported struct {
+		A []int `arg:"0"`
+	}
+	type gap struct {
+		A string `arg:"1"`
+	}
+	type unexported struct {
+		name string `arg:"0"`
+	}
+
+	cases := map[string]interface{}{
+		"not a func":       "deploy",
+		"wrong signature":  func(*deployOptions) error { return nil },
+		"not a struct":     func(context.Context, *string, io.Writer) error { return nil },
+		"unsupported type": func(context.Context, *unsupported, io.Writer) error { return nil },
+		"position gap":     func(context.Context, *gap, io.Writer) error { return nil },
+		"unexported field": func(context.Context, *unexported, io.Writer) error { return nil },
+	}
+
+	for name, fn := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("the `Bind` function did not panic for %s", name)
+				}
+			}()
+			Bind(Command{Keyword: "invalid"}, fn)
+		}()
+	}
+}
@@ -1,11 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"strings"
 
 	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
 )
 
 const (
@@ -14,65 +15,113 @@ const (
 	examplePrefix     = " > "
 	descriptionPrefix = " - "
 	argumentSeparator = " "
+	columnGap         = "   "
 )
 
-// exec runs the Func from a `lime.Command`
-func exec(c *lime.Command, args []string, out io.Writer) error {
-	if c.Func == nil {
+// exec runs the matched `lime.Command` of a route, wrapped in the CLI's middleware and the middleware of each command
+// along the route. The CLI's middleware runs first, followed by each command's middleware from the top level down to
+// the matched command. Within each, middleware runs in the order it was registered. When panics are recovered, the
+// recovery wraps all of the middleware.
+func (cli CLI) exec(ctx context.Context, r *route, args []string) error {
+	if r.command.ContextFunc == nil && r.command.Func == nil {
 		return errNoFunc
 	}
-	return c.Func(args, out)
-}
-
-func help(c *lime.Command) (string, error) {
-	sb := new(strings.Builder)
-	if len(c.Help) == 0 && len(c.Description) == 0 && len(c.Usage) == 0 {
-		return noInfo, errNoHelp
+	if deprecation := r.command.Deprecation(); len(deprecation) > 0 {
+		_, _ = fmt.Fprintf(cli.err, "warning: %q is deprecated: %s\n", strings.Join(args[:r.depth()], argumentSeparator), deprecation)
 	}
 
-	if len(c.Description) > 0 {
-		_, _ = fmt.Fprintln(sb, c.Description)
+	inherited := inheritedFlags(r.trail)
+	h := func(ctx context.Context, inv lime.Invocation) error {
+		return cli.run(ctx, r.command, inherited, inv)
 	}
-
-	if len(c.Help) > 0 {
-		_, _ = fmt.Fprintln(sb, c.Help)
+	for i := len(r.trail) - 1; i >= 0; i-- {
+		h = wrap(h, r.trail[i].Middleware)
 	}
-
-	for i := range c.Usage {
-		_, _ = fmt.Fprintln(sb, examplePrefix, c.Usage[i].Example)
-		_, _ = fmt.Fprintln(sb, explanationPrefix, c.Usage[i].Explanation)
+	h = wrap(h, cli.middleware)
+	if cli.options&options.RecoverPanics > 0 {
+		h = cli.recoverPanics(h)
 	}
 
-	return sb.String(), nil
+	return h(lime.WithParams(ctx, r.params), lime.Invocation{
+		Path: args[:r.depth()],
+		Args: args[r.depth():],
+		In:   cli.in,
+		Out:  cli.out,
+		Err:  cli.err,
+	})
 }
 
-// triggerHelp checks the args for any of the help flags. Returns true if there was a help flag, false otherwise
-func triggerHelp(args []string) bool {
-	for i := range args {
-		if b, ok := helpFlags[args[i]]; ok {
-			return b
-		}
+// wrap wraps h in the given middleware, so that the first middleware runs first
+func wrap(h lime.Handler, middleware []lime.Middleware) lime.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// run invokes the ContextFunc or Func from a `lime.Command`, after parsing and validating its args and the flags it
+// declares or inherits, along with its PreRun and PostRun hooks. The PostRun hook is deferred, so that it also runs
+// when the function panics. Usage errors carry the usage text rendered by the CLI's HelpRenderer.
+func (cli CLI) run(ctx context.Context, c *lime.Command, inherited []lime.Flag, inv lime.Invocation) (err error) {
+	flags, args, err := parseFlags(c, inherited, inv.Args)
+	if err == nil {
+		err = validateArgs(c, args)
+	}
+	if ue, ok := err.(*usageError); ok {
+		ue.usage = cli.renderer().RenderUsage(commandHelpData(inv.Path, c, inherited))
+	}
+	if err != nil {
+		return err
 	}
 
-	return false
+	ctx = lime.WithFlags(ctx, flags)
+	if c.PostRun != nil {
+		defer func() {
+			if postErr := c.PostRun(ctx, inv); err == nil {
+				err = postErr
+			}
+		}()
+	}
+	if c.PreRun != nil {
+		if err = c.PreRun(ctx, inv); err != nil {
+			return err
+		}
+	}
+	if c.ContextFunc != nil {
+		return c.ContextFunc(ctx, args, inv.Out)
+	}
+	return c.Func(args, inv.Out)
 }
 
-// traverses a given command's Commands field, and any tree sprouting from it, to generate help for each command
-func describeRecursively(c *lime.Command, args []string) string {
-	sb := new(strings.Builder)
-	keyword := strings.Trim(c.Keyword, " ")
-	args = append(args, keyword)
-	if len(keyword) == 0 {
-		return ""
+// start runs the CLI's OnStart hooks, stopping at the first error
+func (cli CLI) start() error {
+	for _, hook := range cli.onStart {
+		if err := hook(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if len(c.Description) > 0 {
-		_, _ = fmt.Fprintf(sb, "%s\n%s%s\n", strings.Join(args, argumentSeparator), descriptionPrefix, c.Description)
+// exit runs all of the CLI's OnExit hooks, returning the first error
+func (cli CLI) exit() error {
+	var err error
+	for _, hook := range cli.onExit {
+		if hookErr := hook(); err == nil {
+			err = hookErr
+		}
 	}
+	return err
+}
 
-	for _, com := range c.Commands {
-		_, _ = sb.WriteString(describeRecursively(&com, args))
+// triggerHelp checks the args for any of the help flags. Returns true if there was a help flag, false otherwise, along
+// with whether the flag asks for the detailed help
+func triggerHelp(args []string) (bool, bool) {
+	for i := range args {
+		if detailed, ok := helpFlags[args[i]]; ok {
+			return true, detailed
+		}
 	}
 
-	return sb.String()
+	return false, false
 }
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
+
+// validateArgs checks the args given to a `lime.Command` against its declared `lime.Argument`s.
+// Commands which declare no arguments accept any args.
+func validateArgs(c *lime.Command, args []string) error {
+	if len(c.Arguments) == 0 {
+		return nil
+	}
+
+	for i, a := range c.Arguments {
+		if a.Variadic {
+			if len(args) <= i && !a.Optional {
+				return &usageError{command: c, err: &lime.ArgumentError{Position: i, Name: a.Name, Reason: "a value is required"}}
+			}
+			for j := i; j < len(args); j++ {
+				if err := validateArg(a, j, args[j]); err != nil {
+					return &usageError{command: c, err: err}
+				}
+			}
+			return nil
+		}
+
+		if len(args) <= i {
+			if !a.Optional {
+				return &usageError{command: c, err: &lime.ArgumentError{Position: i, Name: a.Name, Reason: "a value is required"}}
+			}
+			continue
+		}
+
+		if err := validateArg(a, i, args[i]); err != nil {
+			return &usageError{command: c, err: err}
+		}
+	}
+
+	if len(args) > len(c.Arguments) {
+		n := len(c.Arguments)
+		return &usageError{command: c, err: &lime.ArgumentError{Position: n, Reason: fmt.Sprintf("unexpected value %q", args[n])}}
+	}
+
+	return nil
+}
+
+// validateArg checks a single value against the type of a `lime.Argument`
+func validateArg(a lime.Argument, position int, value string) error {
+	var reason string
+	switch a.Type {
+	case lime.IntArgument:
+		if _, err := strconv.Atoi(value); err != nil {
+			reason = fmt.Sprintf("%q is not an integer", value)
+		}
+	case lime.PathArgument:
+		if _, err := os.Stat(value); err != nil {
+			reason = fmt.Sprintf("%q is not an existing path", value)
+		}
+	case lime.EnumArgument:
+		reason = fmt.Sprintf("%q is not one of %s", value, strings.Join(a.Enum, ", "))
+		for _, e := range a.Enum {
+			if e == value {
+				reason = ""
+				break
+			}
+		}
+	}
+
+	if len(reason) > 0 {
+		return &lime.ArgumentError{Position: position, Name: a.Name, Reason: reason}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
+)
+
+// BannerData is the information available when rendering the banner of the interactive mode
+type BannerData struct {
+	// The name of the CLI
+	Name string
+	// The version of the CLI
+	Version string
+	// The number of commands in the CLI, including nested commands
+	CommandCount int
+	// A usage example drawn at random from the commands of the CLI, or nil if there are none
+	Tip *lime.Usage
+}
+
+// BannerFunc is the signature of a function which renders the banner of the interactive mode
+type BannerFunc func(data BannerData) string
+
+// SetVersion takes a string as the CLI application's version, used in the banner of the interactive mode
+func (cli *CLI) SetVersion(version string) {
+	cli.version = version
+}
+
+// SetBannerFunc takes a function to render the banner shown when interactive mode starts
+func (cli *CLI) SetBannerFunc(f BannerFunc) {
+	cli.bannerFunc = f
+}
+
+// SetTipSource takes the source of randomness used to pick the tip shown in the banner of the interactive mode. By
+// default, the source is seeded with the time the CLI was created.
+func (cli *CLI) SetTipSource(src rand.Source) {
+	cli.tips = rand.New(src)
+}
+
+// SetBannerTemplate takes a text/template to render the banner shown when interactive mode starts. The template is
+// executed with a BannerData, and can use the same functions as a prompt template, such as
+// `{{.Name}} {{.Version}} ({{.CommandCount}} commands){{"\n"}}`.
+// Returns an error if the template can not be parsed.
+func (cli *CLI) SetBannerTemplate(text string) error {
+	t, err := template.New("banner").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return err
+	}
+	cli.bannerFunc = func(data BannerData) string {
+		sb := new(strings.Builder)
+		if err := t.Execute(sb, data); err != nil {
+			return defaultBanner(data)
+		}
+		return sb.String()
+	}
+	return nil
+}
+
+// banner renders the banner shown when interactive mode starts. Returns an empty string if the NoBanner option is set.
+// The default banner includes a tip if the BannerTips option is set.
+func (cli CLI) banner() string {
+	if cli.options&options.NoBanner > 0 {
+		return ""
+	}
+
+	usages := make([]lime.Usage, 0)
+	count := countCommands(cli.commands, &usages)
+	data := BannerData{
+		Name:         cli.name,
+		Version:      cli.version,
+		CommandCount: count,
+	}
+	if len(usages) > 0 {
+		tips := cli.tips
+		if tips == nil {
+			tips = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		data.Tip = &usages[tips.Intn(len(usages))]
+	}
+
+	if cli.bannerFunc != nil {
+		return cli.bannerFunc(data)
+	}
+	if cli.options&options.BannerTips == 0 {
+		data.Tip = nil
+	}
+	return defaultBanner(data)
+}
+
+// defaultBanner renders the banner shown when interactive mode starts, unless it is customized
+func defaultBanner(data BannerData) string {
+	sb := &strings.Builder{}
+
+	sb.WriteString("entering interactive mode")
+	if len(data.Name) > 0 {
+		_, _ = fmt.Fprintf(sb, " for %s\n", data.Name)
+	} else {
+		_, _ = fmt.Fprintln(sb)
+	}
+
+	if data.Tip != nil {
+		_, _ = fmt.Fprintf(sb, "tip: %s%s%s\n", data.Tip.Example, descriptionPrefix, data.Tip.Explanation)
+	}
+
+	return sb.String()
+}
+
+// countCommands counts the commands in a tree which have keywords and are not hidden, collecting their usage examples
+// along the way
+func countCommands(commands []lime.Command, usages *[]lime.Usage) int {
+	count := 0
+	for i := range commands {
+		if len(strings.Trim(commands[i].Keyword, " ")) == 0 || commands[i].Hidden {
+			continue
+		}
+		count++
+		*usages = append(*usages, commands[i].Usage...)
+		count += countCommands(commands[i].Commands, usages)
+	}
+	return count
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
+)
+
+const (
+	clearScreen    = "\033[H\033[2J"
+	maxSourceDepth = 16
+	commentPrefix  = "#"
+	aliasSeparator = "="
+	helpKeyword    = "help"
+)
+
+// builtin is a command built into the interactive mode, which is only available when its option is set
+type builtin struct {
+	keyword     string
+	option      lime.Option
+	description string
+	run         func(sh *shell, args []string) error
+}
+
+// builtins are the commands built into the interactive mode, keyed by keyword. Commands defined for the CLI take
+// precedence over them.
+var builtins map[string]builtin
+
+func init() {
+	builtins = make(map[string]builtin)
+	for _, b := range []builtin{
+		{helpKeyword, options.BuiltinHelp, "Shows the help for all commands or the given command, or searches them", builtinHelp},
+		{"history", options.BuiltinHistory, "Lists the input given in this session", builtinHistory},
+		{"clear", options.BuiltinClear, "Clears the screen", builtinClear},
+		{"source", options.BuiltinSource, "Runs each line of the given file as input", builtinSource},
+		{"alias", options.BuiltinAlias, "Lists aliases, or defines one such as `alias name=command args`", builtinAlias},
+		{"set", options.BuiltinSet, "Lists variables, or sets one such as `set name=value`", builtinSet},
+		{"prompt", options.BuiltinPrompt, "Sets the prompt template for this session, or resets it", builtinPrompt},
+	} {
+		builtins[b.keyword] = b
+	}
+}
+
+// builtin returns the builtin with the given keyword, if it is enabled by the CLI's options
+func (sh *shell) builtin(keyword string) (builtin, bool) {
+	b, ok := builtins[keyword]
+	if !ok || sh.cli.options&b.option == 0 {
+		return builtin{}, false
+	}
+	return b, true
+}
+
+// describeBuiltins collects the help entries of the builtins enabled by the CLI's options
+func (sh *shell) describeBuiltins() []CommandHelpData {
+	keywords := make([]string, 0, len(builtins))
+	for keyword := range builtins {
+		if _, ok := sh.builtin(keyword); ok {
+			keywords = append(keywords, keyword)
+		}
+	}
+	sort.Strings(keywords)
+
+	entries := make([]CommandHelpData, len(keywords))
+	for i, keyword := range keywords {
+		c := &lime.Command{Keyword: keyword, Description: builtins[keyword].description}
+		entries[i] = commandHelpData([]string{keyword}, c, nil)
+	}
+	return entries
+}
+
+// builtinHelp shows the help for all commands and builtins, or for the command matching the args. Like the help
+// flags, `-h` shows the compact help for the command and `--usage` shows the detailed help. `help search <term>`
+// searches the commands.
+func builtinHelp(sh *shell, args []string) error {
+	path := make([]string, 0, len(args))
+	flags := make([]string, 0)
+	for _, arg := range args {
+		if _, ok := helpFlags[arg]; ok {
+			flags = append(flags, arg)
+		} else {
+			path = append(path, arg)
+		}
+	}
+
+	if len(path) > 0 && path[0] != searchKeyword {
+		if !strings.HasPrefix(path[0], absolutePath) {
+			path = append(append([]string{}, sh.path...), path...)
+		} else {
+			path = append([]string{strings.TrimPrefix(path[0], absolutePath)}, path[1:]...)
+		}
+	}
+	str, err := sh.cli.helpFor(append(path, flags...), sh.describeBuiltins()...)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(sh.cli.out, str)
+	return err
+}
+
+// builtinHistory lists the input given in the session, numbered from 1
+func builtinHistory(sh *shell, _ []string) error {
+	sb := new(strings.Builder)
+	for i, line := range sh.session.History() {
+		_, _ = fmt.Fprintf(sb, "%5d  %s\n", i+1, line)
+	}
+	_, err := fmt.Fprint(sh.cli.out, sb.String())
+	return err
+}
+
+// builtinClear clears the screen
+func builtinClear(sh *shell, _ []string) error {
+	_, err := fmt.Fprint(sh.cli.out, clearScreen)
+	return err
+}
+
+// builtinSource runs each line of a file as input
+func builtinSource(sh *shell, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: source <file>")
+	}
+	return sh.source(args[0])
+}
+
+// source runs each line of a file as input, skipping blank lines and comments. Stops at the first error.
+func (sh *shell) source(path string) error {
+	if sh.sourceDepth >= maxSourceDepth {
+		return fmt.Errorf("source: scripts are nested more than %d deep", maxSourceDepth)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	defer f.Close()
+
+	sh.sourceDepth++
+	defer func() { sh.sourceDepth-- }()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		// Trailing spaces are kept, since they may be significant, such as in a prompt template
+		line := strings.TrimRight(strings.TrimLeft(scanner.Text(), " \t"), "\r")
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+		if err := sh.run(line); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// builtinAlias lists the aliases of the session, shows a single alias, or defines one with `alias name=command args`
+func builtinAlias(sh *shell, args []string) error {
+	if len(args) == 0 {
+		names := make([]string, 0, len(sh.aliases))
+		for name := range sh.aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sb := new(strings.Builder)
+		for _, name := range names {
+			_, _ = fmt.Fprintf(sb, "%s%s%s\n", name, aliasSeparator, sh.aliases[name])
+		}
+		_, err := fmt.Fprint(sh.cli.out, sb.String())
+		return err
+	}
+
+	definition := strings.Join(args, argumentSeparator)
+	i := strings.Index(definition, aliasSeparator)
+	if i < 0 {
+		value, ok := sh.aliases[definition]
+		if !ok {
+			return fmt.Errorf("alias: %s: not found", definition)
+		}
+		_, err := fmt.Fprintf(sh.cli.out, "%s%s%s\n", definition, aliasSeparator, value)
+		return err
+	}
+
+	name, value := definition[:i], definition[i+1:]
+	if len(name) == 0 || strings.Contains(name, argumentSeparator) {
+		return fmt.Errorf("alias: %q is not a valid name", name)
+	}
+	if len(value) == 0 {
+		delete(sh.aliases, name)
+		return nil
+	}
+	sh.aliases[name] = value
+	return nil
+}
+
+// builtinSet lists the variables of the session, or sets one with `set name=value`. An empty value unsets the variable.
+func builtinSet(sh *shell, args []string) error {
+	if len(args) == 0 {
+		vars := sh.session.Vars()
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sb := new(strings.Builder)
+		for _, name := range names {
+			_, _ = fmt.Fprintf(sb, "%s%s%s\n", name, aliasSeparator, vars[name])
+		}
+		_, err := fmt.Fprint(sh.cli.out, sb.String())
+		return err
+	}
+
+	definition := strings.Join(args, argumentSeparator)
+	i := strings.Index(definition, aliasSeparator)
+	if i <= 0 || strings.Contains(definition[:i], argumentSeparator) {
+		return errors.New("usage: set name=value")
+	}
+	if name, value := definition[:i], definition[i+1:]; len(value) > 0 {
+		sh.session.SetVar(name, value)
+	} else {
+		sh.session.DeleteVar(name)
+	}
+	return nil
+}
+
+// builtinPrompt sets the prompt template of the session, or resets it to the CLI's prompt when given no template
+func builtinPrompt(sh *shell, args []string) error {
+	if len(args) == 0 {
+		sh.promptFunc = nil
+		return nil
+	}
+	f, err := promptTemplate(strings.Join(args, argumentSeparator))
+	if err != nil {
+		return fmt.Errorf("prompt: %w", err)
+	}
+	sh.promptFunc = f
+	return nil
+}
+
+// expandAlias replaces the first word of the input if it is an alias. Aliases are not expanded recursively.
+func (sh *shell) expandAlias(input string) string {
+	if _, ok := sh.builtin("alias"); !ok {
+		return input
+	}
+	words := strings.SplitN(input, argumentSeparator, 2)
+	value, ok := sh.aliases[words[0]]
+	if !ok {
+		return input
+	}
+	if len(words) > 1 {
+		return value + argumentSeparator + words[1]
+	}
+	return value
+}
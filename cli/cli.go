@@ -1,12 +1,13 @@
 package cli
 
 import (
-	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/dotvezz/lime"
 	"github.com/dotvezz/lime/options"
@@ -14,14 +15,23 @@ import (
 
 // CLI is the private struct which holds pointers to the CLI's internal values
 type CLI struct {
-	options  lime.Option
-	commands []lime.Command
-	name     string
-	prompt   string
-	exitWord string
-	out      io.Writer
-	in       io.Reader
-	err      io.Writer
+	options      lime.Option
+	commands     []lime.Command
+	middleware   []lime.Middleware
+	onStart      []func() error
+	onExit       []func() error
+	name         string
+	version      string
+	bannerFunc   BannerFunc
+	tips         *rand.Rand
+	prompt       string
+	promptFunc   PromptFunc
+	helpRenderer HelpRenderer
+	groupOrder   []string
+	exitWord     string
+	out          io.Writer
+	in           io.Reader
+	err          io.Writer
 }
 
 // New creates a new CLI
@@ -32,8 +42,10 @@ func New() *CLI {
 		commands: make([]lime.Command, 0),
 		prompt:   defaultPrompt,
 		exitWord: defaultExitWord,
+		tips:     rand.New(rand.NewSource(time.Now().UnixNano())),
 		out:      os.Stdout,
 		in:       os.Stdin,
+		err:      os.Stderr,
 	}
 }
 
@@ -51,11 +63,35 @@ func (cli *CLI) SetOptions(opts ...lime.Option) error {
 }
 
 // SetCommands takes a variadic list of Commands and stores them in the CLI
+// Returns an error, storing none of the Commands, if any of them are not valid, see `validateCommands`
 func (cli *CLI) SetCommands(commands ...lime.Command) error {
+	all := append(append([]lime.Command{}, cli.commands...), commands...)
+	if err := validateCommands(all, make([]string, 0), nil); err != nil {
+		return err
+	}
 	cli.commands = append(cli.commands, commands...)
 	return nil
 }
 
+// Use takes a variadic list of Middleware which wraps the invocation of every command.
+// The CLI's middleware runs before any middleware registered on the commands themselves.
+func (cli *CLI) Use(middleware ...lime.Middleware) {
+	cli.middleware = append(cli.middleware, middleware...)
+}
+
+// OnStart takes a variadic list of hooks to run once before any command, either before a single command given as
+// args, or when interactive mode starts. If a hook returns an error, the remaining hooks and the command are skipped.
+func (cli *CLI) OnStart(hooks ...func() error) {
+	cli.onStart = append(cli.onStart, hooks...)
+}
+
+// OnExit takes a variadic list of hooks to run once after all commands, either after a single command given as args,
+// or when interactive mode ends through the exit word or the end of input. The hooks run even if an OnStart hook or
+// the command returned an error.
+func (cli *CLI) OnExit(hooks ...func() error) {
+	cli.onExit = append(cli.onExit, hooks...)
+}
+
 // SetName takes a string as the CLI application's name, used in some out
 func (cli *CLI) SetName(name string) {
 	cli.name = name
@@ -87,38 +123,57 @@ func (cli *CLI) SetErrOutput(w io.Writer) {
 }
 
 // Run finds a matching Command for the arguments given and invokes its Func.
+// With no arguments, Run uses the program's arguments, and launches interactive mode if there are none of those either.
+// When the input is not a terminal, the interactive mode runs each line of the input as a batch.
+// When the RunControl option is set, the interactive mode first sources `rc.lime` from the CLI's directory under the
+// user's config directory. The program's arguments may be `--norc` to skip the rc file, or `--rc <file>` to source a
+// different one.
 func (cli CLI) Run(args ...string) error {
-	fromOS := false
 	if len(args) == 0 {
 		args = os.Args[1:]
-		fromOS = true
 	}
+	rc, rcChosen, args := cli.runControl(args)
 	// Go to interactive mode if it's not disabled and there are no args
-	if len(args) == 1 && fromOS || len(args) == 0 {
+	if len(args) == 0 {
 		if cli.options&options.NoInteractiveMode == 0 {
-			cli.interactive()
+			return cli.interactive(rc, rcChosen)
 		}
 		if cli.options&options.PrintErrors > 0 {
 			_, _ = fmt.Fprintln(cli.err, errNoInput.Error())
 		}
 		return errNoInput
 	}
-	c, depth, err := match(cli.commands, args, 1)
+	r, err := match(cli.commands, args)
+
+	// The help subcommand shows the same help as the help flags, unless a command takes precedence
+	if err != nil && args[0] == helpKeyword && cli.options&options.BuiltinHelp > 0 {
+		var helpStr string
+		helpStr, err = cli.helpFor(args[1:])
+		if err == nil {
+			_, err = fmt.Fprint(cli.out, helpStr)
+		} else if cli.options&options.PrintErrors > 0 {
+			_, _ = fmt.Fprintln(cli.err, err.Error())
+		}
+		return err
+	}
+
+	// The hidden spec command writes the command tree as JSON, unless a command takes precedence
+	if err != nil && args[0] == specKeyword && cli.options&options.SpecCommand > 0 {
+		return cli.WriteSpec(cli.out)
+	}
 
 	// Custom flag.Usage for extended help out
 	flag.CommandLine = flag.NewFlagSet(args[0], flag.ContinueOnError)
 	flag.Usage = func() {
-		var helpStr string
-		if err == nil {
-			helpStr, _ = help(c)
-		} else {
+		helpStr, err := cli.helpFor(args)
+		if err != nil {
 			helpStr = cli.help()
 		}
 		fmt.Fprint(cli.out, helpStr)
 	}
 
 	flag.CommandLine.Parse(args)
-	if triggerHelp(args) {
+	if isHelp, _ := triggerHelp(args); isHelp {
 		flag.Usage()
 		return nil
 	}
@@ -130,7 +185,17 @@ func (cli CLI) Run(args ...string) error {
 		return err
 	}
 
-	err = exec(c, args[depth:], cli.out)
+	session := lime.NewSession()
+	err = cli.start()
+	if err == nil {
+		err = cli.exec(lime.WithSession(context.Background(), session), r, args)
+	}
+	if exitErr := cli.exit(); err == nil {
+		err = exitErr
+	}
+	if closeErr := session.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		if cli.options&options.PrintErrors > 0 {
 			_, _ = fmt.Fprintln(cli.err, err.Error())
@@ -138,45 +203,3 @@ func (cli CLI) Run(args ...string) error {
 	}
 	return err
 }
-
-// interactive launches the program in interactive mode
-func (cli CLI) interactive() {
-	sb := &strings.Builder{}
-
-	sb.WriteString("entering interactive mode")
-	if len(cli.name) > 1 {
-		_, _ = fmt.Fprintf(sb, " for %s\n", cli.name)
-	} else {
-		_, _ = fmt.Fprintln(sb)
-	}
-
-	_, err := fmt.Fprint(cli.out, sb.String())
-	if err != nil {
-		panic(err)
-	}
-
-	scanner := bufio.NewScanner(cli.in)
-	for {
-		_, err = fmt.Fprintf(cli.out, "%s ", cli.prompt)
-		if err != nil {
-			panic(err)
-		}
-		scanner.Scan()
-		input := scanner.Text()
-		if input == cli.exitWord {
-			break
-		}
-		args := strings.Split(input, " ")
-		c, depth, err := match(cli.commands, args, 0)
-		if err != nil {
-			if err != errNoMatch || len(input) > 0 {
-				_, _ = fmt.Fprintln(cli.out, err)
-			}
-			continue
-		}
-		err = exec(c, args[depth:], cli.out)
-		if err != nil {
-			_, _ = fmt.Fprintln(cli.out, err)
-		}
-	}
-}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
+)
+
+func TestBuiltins(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "repeat",
+			Description: "Repeats the words",
+			Func: func(args []string, out io.Writer) error {
+				_, _ = fmt.Fprintln(out, args)
+				return nil
+			},
+		},
+		lime.Command{
+			Keyword: "clear",
+			Func: func(_ []string, out io.Writer) error {
+				_, _ = fmt.Fprintln(out, "user clear")
+				return nil
+			},
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	// Ensure builtins are not available unless enabled
+	sh := newShell(*c)
+	if err := sh.execute("history"); err != errNoMatch {
+		t.Errorf("a disabled builtin was available, got %v", err)
+	}
+
+	// Ensure the `help` builtin shows the help, or searches the commands
+	{
+		_ = c.SetOptions(options.BuiltinHelp)
+		sh = newShell(*c)
+		buffer.Reset()
+		_ = sh.execute("help")
+		expect := "repeat   Repeats the words\nhelp     Shows the help for all commands or the given command, or searches them\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+
+		buffer.Reset()
+		_ = sh.execute("help repeat")
+		if str := buffer.String(); str != "Repeats the words\n" {
+			t.Errorf("the `help` builtin did not show the help for the command, got %q", str)
+		}
+
+		buffer.Reset()
+		_ = sh.execute("help search words")
+		if str := buffer.String(); str != "repeat   Repeats the words\n" {
+			t.Errorf("the `help` builtin did not search the commands, got %q", str)
+		}
+	}
+
+	// Ensure the `history` builtin lists the input of the session
+	{
+		_ = c.SetOptions(options.BuiltinHistory)
+		sh = newShell(*c)
+		buffer.Reset()
+		_ = sh.execute("repeat a")
+		buffer.Reset()
+		_ = sh.execute("history")
+
+		expect := "    1  repeat a\n    2  history\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+	}
+
+	// Ensure a command defined for the CLI takes precedence over a builtin
+	{
+		_ = c.SetOptions(options.BuiltinClear)
+		sh = newShell(*c)
+		buffer.Reset()
+		_ = sh.execute("clear")
+		if str := buffer.String(); str != "user clear\n" {
+			t.Errorf("the `clear` builtin took precedence over the `clear` command, got %q", str)
+		}
+	}
+
+	_ = c.SetOptions(options.BuiltinAlias, options.BuiltinSource)
+	sh = newShell(*c)
+	buffer.Reset()
+
+	// Ensure aliases expand the first word of the input
+	{
+		if err := sh.execute("alias say=repeat loudly"); err != nil {
+			t.Errorf("the `alias` builtin returned an error: %s", err)
+		}
+
+		_ = sh.execute("say hello")
+		if str := buffer.String(); str != "[loudly hello]\n" {
+			t.Errorf("the alias was not expanded, got %q", str)
+		}
+
+		buffer.Reset()
+		_ = sh.execute("alias")
+		if str := buffer.String(); str != "say=repeat loudly\n" {
+			t.Errorf("the `alias` builtin did not list the aliases, got %q", str)
+		}
+	}
+
+	// Ensure scripts run each line, skipping comments, and stop at the first error
+	{
+		script := filepath.Join(t.TempDir(), "script.lime")
+		_ = os.WriteFile(script, []byte("# a comment\nsay one\n\nrepeat two\ninvalid\nrepeat three\n"), 0600)
+
+		buffer.Reset()
+		err := sh.execute("source " + script)
+
+		if expect := fmt.Sprintf("%s:5: %s", script, errNoMatch); err == nil || err.Error() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+		}
+
+		if str := buffer.String(); str != "[loudly one]\n[two]\n" {
+			t.Errorf("the `source` builtin did not run the script, got %q", str)
+		}
+	}
+
+	// Ensure the `set` and `prompt` builtins set the variables and the prompt
+	{
+		_ = c.SetOptions(options.BuiltinSet, options.BuiltinPrompt)
+		sh = newShell(*c)
+		buffer.Reset()
+		_ = sh.execute("set env=prod")
+		_ = sh.execute("set region=eu")
+		_ = sh.execute("set region=")
+		_ = sh.execute("set")
+		if str := buffer.String(); str != "env=prod\n" {
+			t.Errorf("the `set` builtin did not list the variables, got %q", str)
+		}
+
+		_ = sh.execute("prompt [{{.Vars.env}}]> ")
+		if p := sh.prompt(); p != "[prod]> " {
+			t.Errorf("the `prompt` builtin did not set the prompt, got %q", p)
+		}
+
+		_ = sh.execute("prompt")
+		if p := sh.prompt(); p != "> " {
+			t.Errorf("the `prompt` builtin did not reset the prompt, got %q", p)
+		}
+	}
+}
@@ -1,19 +1,401 @@
 package cli
 
 import (
+	"fmt"
 	"strings"
+	"text/template"
+
+	"github.com/dotvezz/lime"
 )
 
+// otherGroup heads the commands with no group, when other commands have groups
+const otherGroup = "Other commands"
+
+// helpFlags holds the flags which show help in place of running a command, mapped to whether they ask for the
+// detailed help
 var helpFlags = map[string]bool{
-	"-h":      true,
-	"--help":  true,
+	"-h":      false,
+	"--help":  false,
 	"--usage": true,
 }
 
-func (cli CLI) help() string {
+// HelpData is the information available when rendering the help for a whole CLI
+type HelpData struct {
+	// The name of the CLI
+	Name string
+	// Each command with a keyword, including nested commands, parents before their nested commands. In interactive mode,
+	// the enabled builtins follow the commands.
+	Commands []CommandHelpData
+	// The groups of the commands, in the order set with SetGroupOrder followed by any others in the order they first
+	// appear. Empty if no command has a group.
+	Groups []string
+	// The width of the output stream in columns, to wrap the help to
+	Width int
+}
+
+// CommandHelpData is the information available when rendering the help or usage of a single command
+type CommandHelpData struct {
+	// The keywords leading to the command, including its own
+	Path []string
+	// A one-line summary of how to invoke the command, such as `repeat [flags] <word>...`
+	Synopsis string
+	// The command itself
+	Command *lime.Command
+	// The group the command is listed under, which is the group of its parent unless it has its own
+	Group string
+	// The persistent flags the command inherits from the commands leading to it
+	InheritedFlags []lime.Flag
+	// Whether the detailed help was asked for through `--usage`, rather than the compact help of `-h` or `--help`
+	Detailed bool
+	// The width of the output stream in columns, to wrap the help to
+	Width int
+}
+
+// HelpRenderer renders the help and usage text of a CLI
+type HelpRenderer interface {
+	// RenderHelp renders the help for the whole CLI, shown for a help flag which matches no command
+	RenderHelp(data HelpData) string
+	// RenderCommandHelp renders the compact or detailed help for a single command, shown for a help flag given to the
+	// command
+	RenderCommandHelp(data CommandHelpData) string
+	// RenderUsage renders the usage text shown after the error when the args given to a command are invalid
+	RenderUsage(data CommandHelpData) string
+}
+
+// HelpTemplates holds text/templates to render the help and usage text of a CLI. Each template can use the same
+// functions as a prompt template. An empty template leaves that text to the default renderer.
+type HelpTemplates struct {
+	// Executed with a HelpData, such as `{{range .Commands}}{{join .Path}}: {{.Command.Description}}{{"\n"}}{{end}}`
+	Help string
+	// Executed with a CommandHelpData, such as `{{.Synopsis}}{{"\n"}}{{.Command.Help}}{{"\n"}}`
+	Command string
+	// Executed with a CommandHelpData, such as `usage: {{.Synopsis}}`
+	Usage string
+}
+
+// SetHelpRenderer takes a HelpRenderer to render the help and usage text of the CLI in place of the default layout
+func (cli *CLI) SetHelpRenderer(r HelpRenderer) {
+	cli.helpRenderer = r
+}
+
+// SetGroupOrder takes the order to list the groups of commands in, in the help for the whole CLI. Groups which are not
+// given are listed after these in the order they first appear, and commands with no group are listed last.
+func (cli *CLI) SetGroupOrder(groups ...string) {
+	cli.groupOrder = groups
+}
+
+// SetHelpTemplates takes text/templates to render the help and usage text of the CLI in place of the default layout.
+// Returns an error if any of the templates can not be parsed.
+func (cli *CLI) SetHelpTemplates(templates HelpTemplates) error {
+	r := templateRenderer{}
+	for _, t := range []struct {
+		name string
+		text string
+		dest **template.Template
+	}{
+		{"help", templates.Help, &r.help},
+		{"command", templates.Command, &r.command},
+		{"usage", templates.Usage, &r.usage},
+	} {
+		if len(t.text) == 0 {
+			continue
+		}
+		parsed, err := template.New(t.name).Funcs(templateFuncs).Parse(t.text)
+		if err != nil {
+			return err
+		}
+		*t.dest = parsed
+	}
+	cli.helpRenderer = r
+	return nil
+}
+
+// renderer returns the CLI's HelpRenderer, or the default one if none was set
+func (cli CLI) renderer() HelpRenderer {
+	if cli.helpRenderer == nil {
+		return defaultHelpRenderer{}
+	}
+	return cli.helpRenderer
+}
+
+// help renders the help for all of the CLI's commands, followed by the given extra entries
+func (cli CLI) help(extra ...CommandHelpData) string {
+	data := HelpData{Name: cli.name, Commands: helpEntries(cli.commands, make([]string, 0), ""), Width: terminalWidth(cli.out)}
+	data.Commands = append(data.Commands, extra...)
+	data.Groups = groups(data.Commands, cli.groupOrder)
+	return cli.renderer().RenderHelp(data)
+}
+
+// groups lists the groups of the given entries, with the groups in the given order first
+func groups(entries []CommandHelpData, order []string) []string {
+	found := make(map[string]bool)
+	for _, e := range entries {
+		found[e.Group] = true
+	}
+
+	listed := make(map[string]bool)
+	groups := make([]string, 0)
+	for _, g := range order {
+		if found[g] && !listed[g] && len(g) > 0 {
+			groups = append(groups, g)
+			listed[g] = true
+		}
+	}
+	for _, e := range entries {
+		if !listed[e.Group] && len(e.Group) > 0 {
+			groups = append(groups, e.Group)
+			listed[e.Group] = true
+		}
+	}
+	return groups
+}
+
+// commandHelp renders the help for the command matched by the args
+func (cli CLI) commandHelp(args []string, r *route, detailed bool) string {
+	data := commandHelpData(args[:r.depth()], r.command, inheritedFlags(r.trail))
+	data.Detailed = detailed
+	data.Width = terminalWidth(cli.out)
+	return cli.renderer().RenderCommandHelp(data)
+}
+
+// helpFor renders the help for the command matching the args, or for the whole CLI followed by the given extra entries
+// if there are no args. The args may contain a help flag, which chooses between the compact and detailed help. Args
+// such as `search deploy` search the commands instead, unless they match a command.
+func (cli CLI) helpFor(args []string, extra ...CommandHelpData) (string, error) {
+	_, detailed := triggerHelp(args)
+	path := make([]string, 0, len(args))
+	for _, arg := range args {
+		if _, ok := helpFlags[arg]; !ok {
+			path = append(path, arg)
+		}
+	}
+	if len(path) == 0 {
+		return cli.help(extra...), nil
+	}
+
+	r, err := match(cli.commands, path)
+	if err != nil && len(path) > 1 && path[0] == searchKeyword {
+		return cli.searchHelp(strings.Join(path[1:], argumentSeparator))
+	}
+	if err != nil {
+		return "", err
+	}
+	return cli.commandHelp(path, r, detailed), nil
+}
+
+// commandHelpData collects the information to render the help or usage of a command
+func commandHelpData(path []string, c *lime.Command, inherited []lime.Flag) CommandHelpData {
+	return CommandHelpData{Path: path, Synopsis: c.Synopsis(inherited), Command: c, InheritedFlags: inherited}
+}
+
+// helpEntries traverses a tree of commands to collect each one with a keyword, parents before their nested commands.
+// Commands with no group of their own are given the group of their parent.
+func helpEntries(commands []lime.Command, path []string, group string) []CommandHelpData {
+	entries := make([]CommandHelpData, 0)
+	for i := range commands {
+		keyword := strings.Trim(commands[i].Keyword, " ")
+		if len(keyword) == 0 || commands[i].Hidden {
+			continue
+		}
+		p := append(append([]string{}, path...), keyword)
+		e := commandHelpData(p, &commands[i], nil)
+		if e.Group = commands[i].Group; len(e.Group) == 0 {
+			e.Group = group
+		}
+		entries = append(entries, e)
+		entries = append(entries, helpEntries(commands[i].Commands, p, e.Group)...)
+	}
+	return entries
+}
+
+// defaultHelpRenderer renders help in lime's own layout
+type defaultHelpRenderer struct{}
+
+// RenderHelp lists the path and description of each command which has a description, in aligned columns. When there
+// are groups, each group gets its own section, followed by a section for the commands with no group.
+func (defaultHelpRenderer) RenderHelp(data HelpData) string {
 	sb := new(strings.Builder)
-	for _, c := range cli.commands {
-		_, _ = sb.WriteString(describeRecursively(&c, make([]string, 0)))
+	if len(data.Groups) == 0 {
+		writeColumns(sb, describedRows(data.Commands, ""), "", data.Width)
+		return sb.String()
+	}
+
+	for _, group := range append(append([]string{}, data.Groups...), "") {
+		rows := describedRows(data.Commands, group)
+		if len(rows) == 0 {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		if len(group) == 0 {
+			group = otherGroup
+		}
+		_, _ = fmt.Fprintf(sb, "%s:\n", group)
+		writeColumns(sb, rows, explanationPrefix, data.Width)
 	}
 	return sb.String()
 }
+
+// describedRows collects the path and description of each command in the group which has a description, noting which
+// commands are deprecated
+func describedRows(entries []CommandHelpData, group string) [][2]string {
+	rows := make([][2]string, 0, len(entries))
+	for _, e := range entries {
+		if len(e.Command.Description) == 0 || e.Group != group {
+			continue
+		}
+		description := e.Command.Description
+		if len(e.Command.Deprecated) > 0 {
+			description += " (deprecated)"
+		}
+		rows = append(rows, [2]string{strings.Join(e.Path, argumentSeparator), description})
+	}
+	return rows
+}
+
+// RenderCommandHelp shows the synopsis, description, help, aliases and deprecation of a command, followed by its
+// arguments, flags and nested commands in aligned columns. Long help wraps with a hanging indent. The detailed help adds the inherited flags and the usage
+// examples.
+func (defaultHelpRenderer) RenderCommandHelp(data CommandHelpData) string {
+	c := data.Command
+	sb := new(strings.Builder)
+	if len(c.Help) == 0 && len(c.Description) == 0 && len(c.Usage) == 0 && len(c.Arguments) == 0 && len(c.Flags) == 0 &&
+		len(c.Commands) == 0 && len(c.Deprecated) == 0 && len(c.Aliases) == 0 {
+		return noInfo
+	}
+	width := data.Width
+	if width <= 0 {
+		width = defaultWidth
+	}
+
+	if len(c.Arguments) > 0 || len(c.Flags) > 0 || (data.Detailed && len(data.InheritedFlags) > 0) {
+		_, _ = fmt.Fprintln(sb, data.Synopsis)
+	}
+
+	if len(c.Description) > 0 {
+		_, _ = fmt.Fprintln(sb, wrapText(c.Description, width, 0))
+	}
+
+	if len(c.Help) > 0 {
+		_, _ = fmt.Fprintln(sb, wrapText(c.Help, width, len(explanationPrefix)))
+	}
+
+	if len(c.Aliases) > 0 {
+		_, _ = fmt.Fprintln(sb, wrapText("aliases: "+strings.Join(c.Aliases, ", "), width, 0))
+	}
+
+	if deprecation := c.Deprecation(); len(deprecation) > 0 {
+		_, _ = fmt.Fprintln(sb, wrapText("deprecated: "+deprecation, width, 0))
+	}
+
+	rows := make([][2]string, 0, len(c.Arguments)+len(c.Flags)+len(data.InheritedFlags)+len(c.Commands))
+	for _, a := range c.Arguments {
+		rows = append(rows, [2]string{a.Name, a.Describe()})
+	}
+
+	flags := c.Flags
+	if data.Detailed {
+		flags = append(append([]lime.Flag{}, flags...), data.InheritedFlags...)
+	}
+	for _, f := range flags {
+		rows = append(rows, [2]string{"--" + f.Name, f.Describe()})
+	}
+
+	for _, nested := range c.Commands {
+		if keyword := strings.Trim(nested.Keyword, " "); len(keyword) > 0 && !nested.Hidden {
+			rows = append(rows, [2]string{keyword, nested.Description})
+		}
+	}
+	writeColumns(sb, rows, explanationPrefix, width)
+
+	if !data.Detailed {
+		return sb.String()
+	}
+
+	indent := len(explanationPrefix) + len(argumentSeparator)
+	for i := range c.Usage {
+		_, _ = fmt.Fprintln(sb, examplePrefix, c.Usage[i].Example)
+		_, _ = fmt.Fprintln(sb, explanationPrefix, wrapText(c.Usage[i].Explanation, width, indent))
+	}
+
+	return sb.String()
+}
+
+// RenderUsage shows the synopsis of a command
+func (defaultHelpRenderer) RenderUsage(data CommandHelpData) string {
+	return "usage: " + data.Synopsis
+}
+
+// templateRenderer renders help through text/templates, leaving any missing template to the default renderer. A
+// template which fails to execute is also rendered by the default renderer.
+type templateRenderer struct {
+	help    *template.Template
+	command *template.Template
+	usage   *template.Template
+}
+
+func (r templateRenderer) RenderHelp(data HelpData) string {
+	if s, ok := execute(r.help, data); ok {
+		return s
+	}
+	return defaultHelpRenderer{}.RenderHelp(data)
+}
+
+func (r templateRenderer) RenderCommandHelp(data CommandHelpData) string {
+	if s, ok := execute(r.command, data); ok {
+		return s
+	}
+	return defaultHelpRenderer{}.RenderCommandHelp(data)
+}
+
+func (r templateRenderer) RenderUsage(data CommandHelpData) string {
+	if s, ok := execute(r.usage, data); ok {
+		return s
+	}
+	return defaultHelpRenderer{}.RenderUsage(data)
+}
+
+// writeColumns writes rows of terms and descriptions, with the descriptions aligned in a column and wrapped to fit the
+// width under a hanging indent. The column takes at most half of the width, and the description of a term too wide
+// for it starts on the next line.
+func writeColumns(sb *strings.Builder, rows [][2]string, indent string, width int) {
+	if width <= 0 {
+		width = defaultWidth
+	}
+	column := 0
+	for _, r := range rows {
+		if n := len(indent) + len(r[0]) + len(columnGap); n > column {
+			column = n
+		}
+	}
+	if column > width/2 {
+		column = width / 2
+	}
+
+	for _, r := range rows {
+		sb.WriteString(indent + r[0])
+		if len(r[1]) == 0 {
+			sb.WriteString("\n")
+			continue
+		}
+		if n := len(indent) + len(r[0]) + len(columnGap); n > column {
+			sb.WriteString("\n" + strings.Repeat(" ", column))
+		} else {
+			sb.WriteString(strings.Repeat(" ", column-len(indent)-len(r[0])))
+		}
+		sb.WriteString(wrapText(r[1], width, column) + "\n")
+	}
+}
+
+// execute renders a template, returning false if there is no template or it fails to execute
+func execute(t *template.Template, data interface{}) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	sb := new(strings.Builder)
+	if err := t.Execute(sb, data); err != nil {
+		return "", false
+	}
+	return sb.String(), true
+}
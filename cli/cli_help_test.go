@@ -2,11 +2,16 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
 )
 
 func TestCLI_Help(t *testing.T) {
@@ -86,3 +91,363 @@ func TestCLI_Help(t *testing.T) {
 		}
 	}
 }
+
+func TestCLI_Help_Arguments(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "repeat",
+			Description: "Repeats all the words after the command.",
+			Arguments: []lime.Argument{
+				{Name: "mode", Description: "How to repeat", Type: lime.EnumArgument, Enum: []string{"loud", "quiet"}},
+				{Name: "word", Description: "The words to repeat", Variadic: true},
+			},
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	_ = c.Run("repeat", "--help")
+
+	expect := "repeat <mode> <word>...\nRepeats all the words after the command.\n" +
+		"   mode   How to repeat (one of: loud, quiet)\n   word   The words to repeat\n"
+	if str := buffer.String(); str != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+	}
+}
+
+type testRenderer struct{}
+
+func (testRenderer) RenderHelp(data HelpData) string {
+	return fmt.Sprintf("%s has %d commands\n", data.Name, len(data.Commands))
+}
+
+func (testRenderer) RenderCommandHelp(data CommandHelpData) string {
+	return strings.Join(data.Path, "/") + "\n"
+}
+
+func (testRenderer) RenderUsage(data CommandHelpData) string {
+	return "try: " + strings.Join(data.Path, "/")
+}
+
+func TestCLI_SetHelpRenderer(t *testing.T) {
+	c := New()
+	c.SetName("myCli")
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "tell",
+			Commands: []lime.Command{
+				{
+					Keyword:   "lie",
+					Arguments: []lime.Argument{{Name: "subject"}},
+					Func: func(_ []string, _ io.Writer) error {
+						return nil
+					},
+				},
+			},
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+	c.SetHelpRenderer(testRenderer{})
+
+	_ = c.Run("--help")
+	if expect := "myCli has 2 commands\n"; buffer.String() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, buffer.String())
+	}
+
+	buffer.Reset()
+	_ = c.Run("tell", "lie", "--help")
+	if expect := "tell/lie\n"; buffer.String() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, buffer.String())
+	}
+
+	err := c.Run("tell", "lie")
+	if expect := "argument 1 (subject): a value is required\ntry: tell/lie"; err == nil || err.Error() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+	}
+}
+
+func TestCLI_SetHelpTemplates(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "greet",
+			Description: "Greets someone",
+			Arguments:   []lime.Argument{{Name: "name"}},
+			Func: func(_ []string, _ io.Writer) error {
+				return nil
+			},
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	if err := c.SetHelpTemplates(HelpTemplates{Help: "{{range .Commands}}{{join .Path}}: {{.Command.Description"}); err == nil {
+		t.Error("the `SetHelpTemplates` method did not return an error for an invalid template")
+	}
+
+	err := c.SetHelpTemplates(HelpTemplates{
+		Help:  `{{range .Commands}}{{join .Path}}: {{.Command.Description}}{{"\n"}}{{end}}`,
+		Usage: `Usage: {{.Synopsis}}`,
+	})
+	if err != nil {
+		t.Fatalf("the `SetHelpTemplates` method returned an error: %s", err)
+	}
+
+	_ = c.Run("--help")
+	if expect := "greet: Greets someone\n"; buffer.String() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, buffer.String())
+	}
+
+	// The command help has no template, so it keeps the default layout
+	buffer.Reset()
+	_ = c.Run("greet", "--help")
+	if expect := "greet <name>\nGreets someone\n   name\n"; buffer.String() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, buffer.String())
+	}
+
+	err = c.Run("greet")
+	if expect := "argument 1 (name): a value is required\nUsage: greet <name>"; err == nil || err.Error() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+	}
+}
+
+func TestCLI_Help_Levels(t *testing.T) {
+	c := New()
+	_ = c.SetOptions(options.BuiltinHelp)
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "db",
+			Description: "Manages the database",
+			Flags:       []lime.Flag{{Name: "host", Description: "The database host", Persistent: true}},
+			Commands: []lime.Command{
+				{
+					Keyword:     "migrate",
+					Description: "Migrates the database",
+					Flags:       []lime.Flag{{Name: "steps", Description: "How many migrations", Default: "1"}},
+					Usage:       []lime.Usage{{Example: "myCli db migrate --steps 2", Explanation: "Runs two migrations"}},
+					ContextFunc: func(_ context.Context, _ []string, _ io.Writer) error {
+						return nil
+					},
+				},
+			},
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	compact := "migrate [flags]\nMigrates the database\n   --steps   How many migrations (default: 1)\n"
+	detailed := "migrate [flags]\nMigrates the database\n   --steps   How many migrations (default: 1)\n" +
+		"   --host    The database host\n >  myCli db migrate --steps 2\n    Runs two migrations\n"
+	cases := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"db", "migrate", "-h"}, compact},
+		{[]string{"db", "migrate", "--help"}, compact},
+		{[]string{"db", "migrate", "--usage"}, detailed},
+		{[]string{"help", "db", "migrate"}, compact},
+		{[]string{"help", "--usage", "db", "migrate"}, detailed},
+		{[]string{"db", "-h"}, "db [flags]\nManages the database\n   --host    The database host\n   migrate   Migrates the database\n"},
+		{[]string{"help"}, "db           Manages the database\ndb migrate   Migrates the database\n"},
+	}
+	for _, tc := range cases {
+		buffer.Reset()
+		if err := c.Run(tc.args...); err != nil {
+			t.Errorf("the `Run` method returned an error for %v: %s", tc.args, err)
+		}
+
+		if str := buffer.String(); str != tc.expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", tc.expect, str)
+		}
+	}
+
+	// Ensure the help subcommand reports a command it can not find
+	if err := c.Run("help", "bogus"); err != errNoMatch {
+		t.Errorf("the `help` subcommand did not return errNoMatch, got %v", err)
+	}
+}
+
+func TestCLI_Help_Width(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "deploy",
+			Description: "Deploys the application",
+			Help:        "Builds the application and ships it to each of the configured servers.",
+			Flags: []lime.Flag{
+				{Name: "env", Description: "The environment to deploy the application to", Default: "staging"},
+				{Name: "a-very-long-flag-name", Description: "Does something"},
+			},
+		},
+	)
+	// The tests treat files as terminals, so $COLUMNS applies to the help written to one
+	out, err := os.CreateTemp(t.TempDir(), "help")
+	if err != nil {
+		t.Fatalf("could not create the output file: %s", err)
+	}
+	defer out.Close()
+	c.SetOutput(out)
+
+	// Ensure help is wrapped to the terminal width, with a hanging indent for long help
+	_ = c.Run("deploy", "-h")
+
+	bs, _ := os.ReadFile(out.Name())
+	expect := "deploy [flags]\n" +
+		"Deploys the application\n" +
+		"Builds the application and ships it\n" +
+		"   to each of the configured servers.\n" +
+		"   --env            The environment to\n" +
+		"                    deploy the\n" +
+		"                    application to\n" +
+		"                    (default: staging)\n" +
+		"   --a-very-long-flag-name\n" +
+		"                    Does something\n"
+	if str := string(bs); str != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if w := terminalWidth(&bytes.Buffer{}); w != defaultWidth {
+		t.Errorf("the `terminalWidth` function returned %d for an output which is not a terminal", w)
+	}
+
+	// Ensure $COLUMNS only applies to a terminal, which the tests stand in for with a file
+	t.Setenv("COLUMNS", "120")
+	if w := terminalWidth(&bytes.Buffer{}); w != defaultWidth {
+		t.Errorf("the `terminalWidth` function used $COLUMNS for an output which is not a terminal, got %d", w)
+	}
+
+	out, err := os.CreateTemp(t.TempDir(), "help")
+	if err != nil {
+		t.Fatalf("could not create the output file: %s", err)
+	}
+	defer out.Close()
+	if w := terminalWidth(out); w != 120 {
+		t.Errorf("the `terminalWidth` function did not use $COLUMNS, got %d", w)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	cases := []struct {
+		text   string
+		width  int
+		indent int
+		expect string
+	}{
+		{"the quick brown fox jumps over the lazy dog", 20, 0, "the quick brown fox\njumps over the lazy\ndog"},
+		{"the quick brown fox jumps over the lazy dog", 30, 4, "the quick brown fox jumps\n    over the lazy dog"},
+		{"first line\nsecond line", 80, 2, "first line\n  second line"},
+		{"a supercalifragilisticexpialidocious word", 20, 0, "a\nsupercalifragilisticexpialidocious\nword"},
+	}
+	for _, tc := range cases {
+		if s := wrapText(tc.text, tc.width, tc.indent); s != tc.expect {
+			t.Errorf("\nExpected: \n%q\nBut Got:\n%q\n", tc.expect, s)
+		}
+	}
+}
+
+func TestCLI_Help_Groups(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{Keyword: "version", Description: "Shows the version"},
+		lime.Command{
+			Keyword:     "user",
+			Description: "Manages users",
+			Group:       "Admin commands",
+			Commands: []lime.Command{
+				{Keyword: "add", Description: "Adds a user"},
+			},
+		},
+		lime.Command{Keyword: "deploy", Description: "Deploys the app", Group: "Resource commands"},
+		lime.Command{Keyword: "scale", Description: "Scales the app", Group: "Resource commands"},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	// Ensure groups are listed in the order they appear, with nested commands in the group of their parent
+	{
+		_ = c.Run("--help")
+
+		expect := "Admin commands:\n   user       Manages users\n   user add   Adds a user\n\n" +
+			"Resource commands:\n   deploy   Deploys the app\n   scale    Scales the app\n\n" +
+			"Other commands:\n   version   Shows the version\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+	}
+
+	// Ensure the group order can be configured
+	{
+		buffer.Reset()
+		c.SetGroupOrder("Resource commands")
+		_ = c.Run("--help")
+
+		expect := "Resource commands:\n   deploy   Deploys the app\n   scale    Scales the app\n\n" +
+			"Admin commands:\n   user       Manages users\n   user add   Adds a user\n\n" +
+			"Other commands:\n   version   Shows the version\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+	}
+}
+
+func TestCLI_Help_HiddenAndDeprecated(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "user",
+			Description: "Manages users",
+			Commands: []lime.Command{
+				{Keyword: "add", Description: "Adds a user"},
+				{Keyword: "purge", Description: "Purges every user", Hidden: true},
+				{Keyword: "rm", Description: "Removes a user", Deprecated: "it was renamed", ReplacedBy: "delete"},
+			},
+		},
+		lime.Command{
+			Keyword:     "debug",
+			Description: "Dumps the internal state",
+			Hidden:      true,
+			Commands: []lime.Command{
+				{Keyword: "memory", Description: "Dumps the memory"},
+			},
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	// Ensure hidden commands are left out, and deprecated commands are marked
+	{
+		_ = c.Run("--help")
+
+		expect := "user       Manages users\nuser add   Adds a user\nuser rm    Removes a user (deprecated)\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+	}
+
+	{
+		buffer.Reset()
+		_ = c.Run("user", "-h")
+
+		expect := "Manages users\n   add   Adds a user\n   rm    Removes a user\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+	}
+
+	// Ensure the help for a deprecated command explains the deprecation
+	{
+		buffer.Reset()
+		_ = c.Run("user", "rm", "-h")
+
+		expect := "Removes a user\ndeprecated: it was renamed, use \"delete\" instead\n"
+		if str := buffer.String(); str != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+		}
+	}
+}
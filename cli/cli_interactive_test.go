@@ -1,16 +1,33 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
 )
 
+// terminalCheck is the CLI's own terminal check, kept before init replaces it
+var terminalCheck = isTerminal
+
+func init() {
+	// The tests drive the interactive mode through pipes, so treat them as terminals, and buffers as pipes
+	isTerminal = func(stream interface{}) bool {
+		_, ok := stream.(*os.File)
+		return ok
+	}
+}
+
 func TestCLI_RunInteractive(t *testing.T) {
 	c := New()
 	_ = c.SetCommands(
@@ -108,3 +125,399 @@ func assertReadString(want string, r io.Reader) (err error) {
 
 	return nil
 }
+
+func TestCLI_RunInteractive_Hooks(t *testing.T) {
+	starts, exits := 0, 0
+	c := New()
+	c.OnStart(func() error {
+		starts++
+		return nil
+	})
+	c.OnExit(func() error {
+		exits++
+		return nil
+	})
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "test",
+			Func: func(_ []string, _ io.Writer) error {
+				return nil
+			},
+		},
+	)
+
+	output, out, _ := os.Pipe()
+	in, input, _ := os.Pipe()
+
+	c.SetOutput(out)
+	c.SetInput(in)
+
+	// Ensure the hooks run once per session, and the session ends at the end of input
+	os.Args = []string{"myCli"}
+	done := make(chan struct{})
+	go func() {
+		_ = c.Run()
+		close(done)
+	}()
+
+	go func() {
+		_, _ = io.Copy(io.Discard, output)
+	}()
+
+	_, _ = fmt.Fprintln(input, "test")
+	_, _ = fmt.Fprintln(input, "test")
+	_ = input.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the interactive mode did not end at the end of input")
+	}
+
+	if starts != 1 || exits != 1 {
+		t.Errorf("the hooks did not run once each, got %d starts and %d exits", starts, exits)
+	}
+}
+
+func TestCLI_RunInteractive_RecoverPanics(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	survived := false
+	c := New()
+	_ = c.SetOptions(options.RecoverPanics)
+	c.SetErrOutput(io.Discard)
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "explode",
+			Func: func(_ []string, _ io.Writer) error {
+				panic("boom")
+			},
+		},
+		lime.Command{
+			Keyword: "test",
+			Func: func(_ []string, _ io.Writer) error {
+				survived = true
+				return nil
+			},
+		},
+	)
+
+	output, out, _ := os.Pipe()
+	in, input, _ := os.Pipe()
+
+	c.SetOutput(out)
+	c.SetInput(in)
+
+	// Ensure the session survives a panicking command
+	os.Args = []string{"myCli"}
+	done := make(chan struct{})
+	go func() {
+		_ = c.Run()
+		close(done)
+	}()
+
+	go func() {
+		_, _ = io.Copy(io.Discard, output)
+	}()
+
+	_, _ = fmt.Fprintln(input, "explode")
+	_, _ = fmt.Fprintln(input, "test")
+	_ = input.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the interactive mode did not end at the end of input")
+	}
+
+	if !survived {
+		t.Error("the interactive mode did not run the command after the panic")
+	}
+}
+
+func TestCLI_RunInteractive_Session(t *testing.T) {
+	var history []string
+	var lastErr error
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "login",
+			ContextFunc: func(ctx context.Context, args []string, _ io.Writer) error {
+				lime.SessionFrom(ctx).Set("user", args[0])
+				return nil
+			},
+		},
+		lime.Command{
+			Keyword: "whoami",
+			ContextFunc: func(ctx context.Context, _ []string, out io.Writer) error {
+				session := lime.SessionFrom(ctx)
+				user, ok := lime.Value[string](session, "user")
+				if !ok {
+					return errors.New("not logged in")
+				}
+				history = session.History()
+				lastErr = session.LastErr()
+				_, _ = fmt.Fprintln(out, user)
+				return nil
+			},
+		},
+	)
+
+	output, out, _ := os.Pipe()
+	in, input, _ := os.Pipe()
+
+	c.SetOutput(out)
+	c.SetInput(in)
+
+	// Ensure the commands of a session share its state
+	os.Args = []string{"myCli"}
+	go func() {
+		_ = c.Run()
+	}()
+
+	if err := assertReadString("entering interactive mode\n> ", output); err != nil {
+		t.Error(err)
+	}
+
+	_, _ = fmt.Fprintln(input, "whoami")
+	if err := assertReadString("not logged in\n> ", output); err != nil {
+		t.Error(err)
+	}
+
+	_, _ = fmt.Fprintln(input, "login john")
+	if err := assertReadString("> ", output); err != nil {
+		t.Error(err)
+	}
+
+	_, _ = fmt.Fprintln(input, "whoami")
+	if err := assertReadString("john\n> ", output); err != nil {
+		t.Error(err)
+	}
+
+	if len(history) != 3 || history[1] != "login john" || lastErr != nil {
+		t.Errorf("the session did not keep the history and last error, got %v and %v", history, lastErr)
+	}
+
+	_, _ = fmt.Fprintln(input, c.exitWord)
+}
+
+func TestCLI_RunInteractive_Namespaces(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "tell",
+			Commands: []lime.Command{
+				{
+					Keyword: "lie",
+					Func: func(_ []string, out io.Writer) error {
+						_, _ = fmt.Fprintln(out, "oranges")
+						return nil
+					},
+				},
+			},
+		},
+		lime.Command{
+			Keyword: "repeat",
+			Func: func(args []string, out io.Writer) error {
+				_, _ = fmt.Fprintln(out, args)
+				return nil
+			},
+		},
+	)
+
+	output, out, _ := os.Pipe()
+	in, input, _ := os.Pipe()
+
+	c.SetOutput(out)
+	c.SetInput(in)
+
+	os.Args = []string{"myCli"}
+	go func() {
+		_ = c.Run()
+	}()
+
+	if err := assertReadString("entering interactive mode\n> ", output); err != nil {
+		t.Error(err)
+	}
+
+	// Ensure a command with nested commands and no Func enters its namespace
+	_, _ = fmt.Fprintln(input, "tell")
+	if err := assertReadString("tell> ", output); err != nil {
+		t.Error(err)
+	}
+
+	// Ensure input is matched relative to the namespace
+	_, _ = fmt.Fprintln(input, "lie")
+	if err := assertReadString("oranges\ntell> ", output); err != nil {
+		t.Error(err)
+	}
+
+	// Ensure absolute paths still work
+	_, _ = fmt.Fprintln(input, "/repeat a b")
+	if err := assertReadString("[a b]\ntell> ", output); err != nil {
+		t.Error(err)
+	}
+
+	// Ensure `..` leaves the namespace
+	_, _ = fmt.Fprintln(input, "..")
+	if err := assertReadString("> ", output); err != nil {
+		t.Error(err)
+	}
+
+	_, _ = fmt.Fprintln(input, "lie")
+	if err := assertReadString(fmt.Sprintf("%s\n> ", errNoMatch.Error()), output); err != nil {
+		t.Error(err)
+	}
+
+	_, _ = fmt.Fprintln(input, c.exitWord)
+}
+
+func TestCLI_RunInteractive_RunControl(t *testing.T) {
+	rc := filepath.Join(t.TempDir(), "rc.lime")
+	_ = os.WriteFile(rc, []byte("set env=prod\nprompt [{{.Vars.env}}]> \n"), 0600)
+
+	c := New()
+	_ = c.SetOptions(options.RunControl, options.BuiltinSet, options.BuiltinPrompt)
+
+	output, out, _ := os.Pipe()
+	in, input, _ := os.Pipe()
+
+	c.SetOutput(out)
+	c.SetInput(in)
+
+	// Ensure the rc file chosen by a flag is sourced when interactive mode starts
+	go func() {
+		_ = c.Run("--rc", rc)
+	}()
+
+	if err := assertReadString("entering interactive mode\n[prod]> ", output); err != nil {
+		t.Error(err)
+	}
+
+	_, _ = fmt.Fprintln(input, c.exitWord)
+}
+
+func TestCLI_runControl(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, _ := os.UserConfigDir()
+	c := New()
+	c.SetName("myCli")
+
+	// Ensure the rc file is ignored without the option
+	if path, _, _ := c.runControl(nil); path != "" {
+		t.Errorf("the rc file was used without the RunControl option, got %q", path)
+	}
+
+	_ = c.SetOptions(options.RunControl)
+	cases := []struct {
+		args   []string
+		path   string
+		chosen bool
+		rest   int
+	}{
+		{[]string{}, filepath.Join(dir, "myCli", "rc.lime"), false, 0},
+		{[]string{"--norc"}, "", true, 0},
+		{[]string{"--rc", "my.lime"}, "my.lime", true, 0},
+		{[]string{"--rc=my.lime", "deploy"}, "my.lime", true, 1},
+	}
+	for _, tc := range cases {
+		path, chosen, rest := c.runControl(tc.args)
+		if path != tc.path || chosen != tc.chosen || len(rest) != tc.rest {
+			t.Errorf("unexpected rc file for %v: %q, %t, %v", tc.args, path, chosen, rest)
+		}
+	}
+}
+
+func TestCLI_RunBatch(t *testing.T) {
+	ran := make([]string, 0)
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "echo",
+			Func: func(args []string, out io.Writer) error {
+				ran = append(ran, args[0])
+				_, _ = fmt.Fprintln(out, args[0])
+				return nil
+			},
+		},
+		lime.Command{
+			Keyword: "fail",
+			Func: func(_ []string, _ io.Writer) error {
+				return errors.New("failed successfully")
+			},
+		},
+	)
+	outBuffer, errBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+	c.SetErrOutput(errBuffer)
+
+	os.Args = []string{"myCli"}
+
+	// Ensure batch mode runs every line without a banner or prompts, and ends at the end of input
+	{
+		c.SetInput(strings.NewReader("echo a\nfail\necho b\n"))
+		err := c.Run()
+
+		if err != nil {
+			t.Errorf("the batch mode returned an error without the StopOnError option: %s", err)
+		}
+
+		if str := outBuffer.String(); str != "a\nb\n" {
+			t.Errorf("the batch mode wrote unexpected output: %q", str)
+		}
+
+		if str := errBuffer.String(); str != "failed successfully\n" {
+			t.Errorf("the batch mode did not write the error to the error output: %q", str)
+		}
+	}
+
+	// Ensure batch mode stops at the first error with the StopOnError option
+	{
+		ran = ran[:0]
+		c.SetInput(strings.NewReader("echo a\nfail\necho b\n"))
+		_ = c.SetOptions(options.StopOnError)
+		err := c.Run()
+
+		if err == nil || err.Error() != "failed successfully" {
+			t.Errorf("the batch mode did not return the error which stopped it, got %v", err)
+		}
+
+		if len(ran) != 1 {
+			t.Errorf("the batch mode did not stop at the first error, ran %v", ran)
+		}
+	}
+}
+
+func TestCLI_RunDevNull(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("only Linux tells character devices apart from terminals")
+	}
+	isTerminal = terminalCheck
+	defer func() {
+		isTerminal = func(stream interface{}) bool {
+			_, ok := stream.(*os.File)
+			return ok
+		}
+	}()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("could not open %s: %s", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	c := New()
+	c.SetName("myCli")
+	outBuffer := &bytes.Buffer{}
+	c.SetInput(devNull)
+	c.SetOutput(outBuffer)
+
+	// Ensure input from /dev/null, as under cron or CI, runs as a quiet batch rather than an interactive session
+	os.Args = []string{"myCli"}
+	if err := c.Run(); err != nil {
+		t.Errorf("the batch mode returned an error for empty input: %s", err)
+	}
+
+	if str := outBuffer.String(); len(str) > 0 {
+		t.Errorf("the CLI treated %s as a terminal, and wrote %q", os.DevNull, str)
+	}
+}
@@ -2,13 +2,16 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
 )
 
 func TestCLI_Run_Basic(t *testing.T) {
@@ -88,6 +91,22 @@ func TestCLI_Run_Basic(t *testing.T) {
 		}
 	}
 
+	// Ensure a single program argument runs its command, rather than starting interactive mode
+	{
+		args := os.Args
+		defer func() { os.Args = args }()
+		os.Args = []string{"myCli", "repeat"}
+		buf := &bytes.Buffer{}
+		c.SetOutput(buf)
+		c.SetInput(strings.NewReader(""))
+
+		err := c.Run()
+
+		if err != nil || buf.String() != "[]\n" {
+			t.Errorf("the `Run` method did not run the command from a single program argument, got %q, %v", buf.String(), err)
+		}
+	}
+
 	//// Ensure an error is returned when there is no command to run and interactive is disabled
 	//{
 	//	_ = c.SetOptions(options.NoInteractiveMode)
@@ -188,3 +207,676 @@ func TestCLI_Run_Captured_IO(t *testing.T) {
 		}
 	}
 }
+
+func TestCLI_Run_Placeholders(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "user",
+			Commands: []lime.Command{
+				{
+					Keyword: "list",
+					Func: func(_ []string, out io.Writer) error {
+						fmt.Fprintln(out, "listed")
+						return nil
+					},
+				},
+				{
+					Keyword: "<id>",
+					Commands: []lime.Command{
+						{
+							Keyword: "delete",
+							ContextFunc: func(ctx context.Context, args []string, out io.Writer) error {
+								fmt.Fprintln(out, "deleted", lime.ParamsFrom(ctx)["id"], args)
+								return nil
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	// Ensure a placeholder captures its value and matching continues into its nested commands
+	{
+		outBuffer.Reset()
+		err := c.Run("user", "42", "delete", "--force")
+
+		if err != nil {
+			t.Error("the `Run` method returned an error for a command that should succeed")
+		}
+
+		if out := outBuffer.String(); out != fmt.Sprintln("deleted 42 [--force]") {
+			t.Errorf("the `Run` method did not pass the captured param to the command, got %q", out)
+		}
+	}
+
+	// Ensure literal keywords take precedence over placeholders
+	{
+		outBuffer.Reset()
+		err := c.Run("user", "list")
+
+		if err != nil {
+			t.Error("the `Run` method returned an error for a command that should succeed")
+		}
+
+		if out := outBuffer.String(); out != fmt.Sprintln("listed") {
+			t.Errorf("the `Run` method matched a placeholder over a literal keyword, got %q", out)
+		}
+	}
+}
+
+func TestCLI_Run_Aliases(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "user",
+			Commands: []lime.Command{
+				{
+					Keyword: "remove",
+					Aliases: []string{"rm", "del"},
+					ContextFunc: func(ctx context.Context, args []string, out io.Writer) error {
+						fmt.Fprintln(out, "removed", args)
+						return nil
+					},
+				},
+				{
+					Keyword: "<id>",
+					ContextFunc: func(ctx context.Context, args []string, out io.Writer) error {
+						fmt.Fprintln(out, "user", lime.ParamsFrom(ctx)["id"])
+						return nil
+					},
+				},
+			},
+		},
+	)
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	// Ensure aliases invoke their command, and take precedence over placeholders
+	cases := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"user", "remove", "1"}, "removed [1]\n"},
+		{[]string{"user", "rm", "1"}, "removed [1]\n"},
+		{[]string{"user", "del", "1"}, "removed [1]\n"},
+		{[]string{"user", "42"}, "user 42\n"},
+	}
+	for _, tc := range cases {
+		outBuffer.Reset()
+		if err := c.Run(tc.args...); err != nil {
+			t.Errorf("the `Run` method returned an error for %v: %s", tc.args, err)
+		}
+
+		if out := outBuffer.String(); out != tc.expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", tc.expect, out)
+		}
+	}
+}
+
+func TestCLI_Run_SpecCommand(t *testing.T) {
+	c := New()
+	c.SetName("myCli")
+	_ = c.SetCommands(lime.Command{Keyword: "greet", Description: "Greets someone"})
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	// Ensure the spec command is not available unless enabled
+	if err := c.Run("__commands"); err != errNoMatch {
+		t.Errorf("the spec command was available without its option, got %v", err)
+	}
+
+	_ = c.SetOptions(options.SpecCommand)
+	if err := c.Run("__commands"); err != nil {
+		t.Errorf("the spec command returned an error: %s", err)
+	}
+
+	expect := `{
+  "name": "myCli",
+  "commands": [
+    {
+      "keyword": "greet",
+      "description": "Greets someone",
+      "runnable": false
+    }
+  ]
+}
+`
+	if out := outBuffer.String(); out != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, out)
+	}
+}
+
+func TestCLI_Run_Arguments(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "repeat",
+			Arguments: []lime.Argument{
+				{Name: "times", Type: lime.IntArgument},
+				{Name: "mode", Type: lime.EnumArgument, Enum: []string{"loud", "quiet"}},
+				{Name: "word", Variadic: true},
+			},
+			Func: func(args []string, out io.Writer) error {
+				fmt.Fprintln(out, args)
+				return nil
+			},
+		},
+		lime.Command{
+			Keyword: "greet",
+			Arguments: []lime.Argument{
+				{Name: "name", Optional: true},
+			},
+			Func: func(args []string, out io.Writer) error {
+				fmt.Fprintln(out, args)
+				return nil
+			},
+		},
+	)
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	// Ensure valid args reach the Func
+	{
+		outBuffer.Reset()
+		err := c.Run("repeat", "2", "loud", "the", "fox")
+
+		if err != nil {
+			t.Errorf("the `Run` method returned an error for valid args: %s", err)
+		}
+
+		if out := outBuffer.String(); out != fmt.Sprintln("[2 loud the fox]") {
+			t.Errorf("the `Run` method did not pass the args to the Func, got %q", out)
+		}
+	}
+
+	// Ensure invalid args are reported by position without invoking the Func
+	cases := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"repeat", "two", "loud", "fox"}, "argument 1 (times): \"two\" is not an integer\nusage: repeat <times> <mode> <word>..."},
+		{[]string{"repeat", "2", "shout", "fox"}, "argument 2 (mode): \"shout\" is not one of loud, quiet\nusage: repeat <times> <mode> <word>..."},
+		{[]string{"repeat", "2", "loud"}, "argument 3 (word): a value is required\nusage: repeat <times> <mode> <word>..."},
+		{[]string{"greet", "John", "Smith"}, "argument 2: unexpected value \"Smith\"\nusage: greet [name]"},
+	}
+	for _, tc := range cases {
+		outBuffer.Reset()
+		err := c.Run(tc.args...)
+
+		if err == nil || err.Error() != tc.expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", tc.expect, err)
+		}
+
+		if outBuffer.Len() > 0 {
+			t.Error("the `Run` method invoked the Func with invalid args")
+		}
+	}
+}
+
+func TestCLI_Run_Flags(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "deploy",
+			Flags: []lime.Flag{
+				{Name: "env", Default: "staging"},
+				{Name: "force", Bool: true},
+				{Name: "region", Required: true},
+			},
+			ContextFunc: func(ctx context.Context, args []string, out io.Writer) error {
+				flags := lime.FlagsFrom(ctx)
+				fmt.Fprintln(out, flags["env"], flags["force"], flags["region"], args)
+				return nil
+			},
+		},
+	)
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	cases := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"deploy", "--region", "us", "api"}, "staging  us [api]\n"},
+		{[]string{"deploy", "api", "-env=prod", "--force", "--region=eu", "-5"}, "prod true eu [api -5]\n"},
+		{[]string{"deploy", "--region", "us", "--", "--force"}, "staging  us [--force]\n"},
+	}
+	for _, tc := range cases {
+		outBuffer.Reset()
+		err := c.Run(tc.args...)
+
+		if err != nil {
+			t.Errorf("the `Run` method returned an error for valid flags: %s", err)
+		}
+
+		if out := outBuffer.String(); out != tc.expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", tc.expect, out)
+		}
+	}
+
+	// Ensure bad flags are reported without invoking the Func
+	failures := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"deploy", "--bogus"}, "flag --bogus: unknown flag\nusage: deploy [flags]"},
+		{[]string{"deploy", "--region"}, "flag --region: a value is required\nusage: deploy [flags]"},
+		{[]string{"deploy", "api"}, "flag --region: the flag is required\nusage: deploy [flags]"},
+	}
+	for _, tc := range failures {
+		outBuffer.Reset()
+		err := c.Run(tc.args...)
+
+		if err == nil || err.Error() != tc.expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", tc.expect, err)
+		}
+
+		if outBuffer.Len() > 0 {
+			t.Error("the `Run` method invoked the Func with invalid flags")
+		}
+	}
+}
+
+func TestCLI_SetCommands_FlagsWithoutContext(t *testing.T) {
+	noop := func(_ []string, _ io.Writer) error {
+		return nil
+	}
+
+	// Ensure a Func-only command can not declare flags, since it has no way to read them
+	c := New()
+	err := c.SetCommands(lime.Command{Keyword: "deploy", Flags: []lime.Flag{{Name: "env"}}, Func: noop})
+	if !errors.Is(err, errFlagsWithoutContext) {
+		t.Errorf("the `SetCommands` method did not reject flags on a Func-only command, got %v", err)
+	}
+	if len(c.commands) > 0 {
+		t.Error("the `SetCommands` method stored commands which are not valid")
+	}
+
+	// Ensure the same goes for flags inherited from a parent
+	err = c.SetCommands(lime.Command{
+		Keyword:  "db",
+		Flags:    []lime.Flag{{Name: "host", Persistent: true}},
+		Commands: []lime.Command{{Keyword: "migrate", Func: noop}},
+	})
+	if expect := "\"db migrate\": " + errFlagsWithoutContext.Error(); err == nil || err.Error() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+	}
+}
+
+func TestCLI_SetCommands_AliasCollision(t *testing.T) {
+	// Ensure an alias can not be the keyword of a sibling
+	c := New()
+	err := c.SetCommands(
+		lime.Command{Keyword: "remove", Aliases: []string{"rm"}},
+		lime.Command{Keyword: "rm"},
+	)
+	if !errors.Is(err, errAliasCollision) {
+		t.Errorf("the `SetCommands` method did not reject an alias used as a keyword, got %v", err)
+	}
+	if len(c.commands) > 0 {
+		t.Error("the `SetCommands` method stored commands which are not valid")
+	}
+
+	// Ensure an alias can not be the alias of a sibling
+	err = c.SetCommands(lime.Command{
+		Keyword: "user",
+		Commands: []lime.Command{
+			{Keyword: "remove", Aliases: []string{"rm"}},
+			{Keyword: "revoke", Aliases: []string{"rm"}},
+		},
+	})
+	if expect := "\"user revoke\": alias \"rm\": " + errAliasCollision.Error(); err == nil || err.Error() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+	}
+
+	// Ensure commands stored earlier are taken into account, but aliases may repeat in different namespaces
+	_ = c.SetCommands(lime.Command{Keyword: "remove", Aliases: []string{"rm"}})
+	if err := c.SetCommands(lime.Command{Keyword: "rm"}); !errors.Is(err, errAliasCollision) {
+		t.Errorf("the `SetCommands` method did not reject a keyword used as an earlier alias, got %v", err)
+	}
+	err = c.SetCommands(lime.Command{
+		Keyword:  "user",
+		Commands: []lime.Command{{Keyword: "remove", Aliases: []string{"rm"}}},
+	})
+	if err != nil || len(c.commands) != 2 {
+		t.Errorf("the `SetCommands` method rejected an alias from another namespace, got %v", err)
+	}
+}
+
+func TestCLI_Run_PersistentFlags(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "db",
+			Flags: []lime.Flag{
+				{Name: "host", Default: "localhost", Persistent: true},
+				{Name: "verbose", Bool: true},
+			},
+			Commands: []lime.Command{
+				{
+					Keyword: "migrate",
+					Flags:   []lime.Flag{{Name: "steps", Default: "1"}},
+					ContextFunc: func(ctx context.Context, args []string, out io.Writer) error {
+						flags := lime.FlagsFrom(ctx)
+						fmt.Fprintln(out, flags["host"], flags["steps"], args)
+						return nil
+					},
+				},
+			},
+		},
+	)
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	// Ensure a nested command accepts the persistent flags of its parents
+	{
+		err := c.Run("db", "migrate", "--host", "db1", "--steps=2", "up")
+
+		if err != nil {
+			t.Errorf("the `Run` method returned an error for an inherited flag: %s", err)
+		}
+
+		if expect := "db1 2 [up]\n"; outBuffer.String() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, outBuffer.String())
+		}
+	}
+
+	// Ensure a nested command does not accept flags which are not persistent
+	{
+		err := c.Run("db", "migrate", "--verbose")
+
+		if expect := "flag --verbose: unknown flag\nusage: migrate [flags]"; err == nil || err.Error() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+		}
+	}
+}
+
+func TestCLI_Run_Deprecated(t *testing.T) {
+	c := New()
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:    "rm",
+			Deprecated: "removing is now done by delete",
+			ReplacedBy: "delete",
+			Func: func(args []string, out io.Writer) error {
+				fmt.Fprintln(out, "removed", args)
+				return nil
+			},
+		},
+	)
+	outBuffer := &bytes.Buffer{}
+	errBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+	c.SetErrOutput(errBuffer)
+
+	if err := c.Run("rm", "a"); err != nil {
+		t.Errorf("the `Run` method returned an error for a deprecated command: %s", err)
+	}
+
+	if expect := "removed [a]\n"; outBuffer.String() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, outBuffer.String())
+	}
+
+	expect := "warning: \"rm\" is deprecated: removing is now done by delete, use \"delete\" instead\n"
+	if errBuffer.String() != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, errBuffer.String())
+	}
+}
+
+func TestCLI_Run_Bind(t *testing.T) {
+	type greetOptions struct {
+		Name  string `arg:"0" required:"true"`
+		Times int    `flag:"times" default:"1"`
+	}
+
+	c := New()
+	_ = c.SetCommands(
+		lime.Bind(lime.Command{Keyword: "greet"}, func(_ context.Context, o *greetOptions, out io.Writer) error {
+			for i := 0; i < o.Times; i++ {
+				fmt.Fprintf(out, "Hello, %s!\n", o.Name)
+			}
+			return nil
+		}),
+	)
+	outBuffer := &bytes.Buffer{}
+	c.SetOutput(outBuffer)
+
+	// Ensure a bound command receives its populated struct
+	{
+		err := c.Run("greet", "John", "--times", "2")
+
+		if err != nil {
+			t.Errorf("the `Run` method returned an error for a bound command: %s", err)
+		}
+
+		if expect := "Hello, John!\nHello, John!\n"; outBuffer.String() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, outBuffer.String())
+		}
+	}
+
+	// Ensure a bound command validates its required argument
+	{
+		err := c.Run("greet")
+
+		if expect := "argument 1 (name): a value is required\nusage: greet [flags] <name>"; err == nil || err.Error() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, err)
+		}
+	}
+}
+
+func TestCLI_Run_Middleware(t *testing.T) {
+	trace := make([]string, 0)
+	tracer := func(name string) lime.Middleware {
+		return func(next lime.Handler) lime.Handler {
+			return func(ctx context.Context, inv lime.Invocation) error {
+				trace = append(trace, fmt.Sprintf("%s %v %v", name, inv.Path, inv.Args))
+				return next(ctx, inv)
+			}
+		}
+	}
+	deny := func(next lime.Handler) lime.Handler {
+		return func(ctx context.Context, inv lime.Invocation) error {
+			return errors.New("denied")
+		}
+	}
+
+	c := New()
+	c.Use(tracer("cli"), tracer("cli2"))
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:    "user",
+			Middleware: []lime.Middleware{tracer("user")},
+			Commands: []lime.Command{
+				{
+					Keyword:    "<id>",
+					Middleware: []lime.Middleware{tracer("id")},
+					Func: func(args []string, _ io.Writer) error {
+						trace = append(trace, fmt.Sprintf("func %v", args))
+						return nil
+					},
+				},
+			},
+		},
+		lime.Command{
+			Keyword:    "admin",
+			Middleware: []lime.Middleware{deny},
+			Func: func(_ []string, _ io.Writer) error {
+				trace = append(trace, "admin")
+				return nil
+			},
+		},
+	)
+
+	// Ensure middleware runs from the CLI down to the matched command, in the order registered
+	{
+		err := c.Run("user", "42", "extra")
+
+		if err != nil {
+			t.Errorf("the `Run` method returned an error for a command that should succeed: %s", err)
+		}
+
+		expect := "cli [user 42] [extra]|cli2 [user 42] [extra]|user [user 42] [extra]|id [user 42] [extra]|func [extra]"
+		if got := strings.Join(trace, "|"); got != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, got)
+		}
+	}
+
+	// Ensure middleware can short-circuit the invocation
+	{
+		trace = trace[:0]
+		err := c.Run("admin")
+
+		if err == nil || err.Error() != "denied" {
+			t.Errorf("the `Run` method did not return the middleware's error, got %v", err)
+		}
+
+		if len(trace) != 2 {
+			t.Errorf("the `Run` method invoked the Func despite the middleware short-circuiting, got %v", trace)
+		}
+	}
+}
+
+func TestCLI_Run_Hooks(t *testing.T) {
+	trace := make([]string, 0)
+	hook := func(name string, err error) lime.Handler {
+		return func(_ context.Context, _ lime.Invocation) error {
+			trace = append(trace, name)
+			return err
+		}
+	}
+
+	c := New()
+	c.OnStart(func() error {
+		trace = append(trace, "start")
+		return nil
+	})
+	c.OnExit(func() error {
+		trace = append(trace, "exit")
+		return nil
+	})
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "fail",
+			PreRun:  hook("pre", nil),
+			PostRun: hook("post", nil),
+			Func: func(_ []string, _ io.Writer) error {
+				trace = append(trace, "func")
+				return errors.New("failed successfully")
+			},
+		},
+		lime.Command{
+			Keyword: "denied",
+			PreRun:  hook("pre", errors.New("denied")),
+			PostRun: hook("post", nil),
+			Func: func(_ []string, _ io.Writer) error {
+				trace = append(trace, "func")
+				return nil
+			},
+		},
+	)
+
+	// Ensure the hooks run around the Func, even when the Func fails
+	{
+		err := c.Run("fail")
+
+		if err == nil || err.Error() != "failed successfully" {
+			t.Errorf("the `Run` method did not return the Func's error, got %v", err)
+		}
+
+		if expect := "start pre func post exit"; strings.Join(trace, " ") != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, strings.Join(trace, " "))
+		}
+	}
+
+	// Ensure a failing PreRun skips the Func, but not the PostRun
+	{
+		trace = trace[:0]
+		err := c.Run("denied")
+
+		if err == nil || err.Error() != "denied" {
+			t.Errorf("the `Run` method did not return the PreRun error, got %v", err)
+		}
+
+		if expect := "start pre post exit"; strings.Join(trace, " ") != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, strings.Join(trace, " "))
+		}
+	}
+}
+
+func TestCLI_Run_HooksRecoverPanics(t *testing.T) {
+	var trace []string
+	c := New()
+	_ = c.SetOptions(options.RecoverPanics)
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "explode",
+			PostRun: func(_ context.Context, _ lime.Invocation) error {
+				trace = append(trace, "post")
+				return nil
+			},
+			Func: func(_ []string, _ io.Writer) error {
+				trace = append(trace, "func")
+				panic("boom")
+			},
+		},
+	)
+	errBuffer := &bytes.Buffer{}
+	c.SetErrOutput(errBuffer)
+
+	// Ensure the PostRun still runs when the Func panics
+	err := c.Run("explode")
+	if i := strings.Index(errBuffer.String(), "written to "); i >= 0 {
+		defer os.Remove(strings.TrimSuffix(errBuffer.String()[i+len("written to "):], ")\n"))
+	}
+	if !errors.Is(err, ErrPanic) {
+		t.Errorf("the `Run` method did not return an error wrapping ErrPanic for a panicking command, got %v", err)
+	}
+	if expect := "func post"; strings.Join(trace, " ") != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, strings.Join(trace, " "))
+	}
+}
+
+func TestCLI_Run_RecoverPanics(t *testing.T) {
+	c := New()
+	c.SetName("myCli")
+	_ = c.SetOptions(options.RecoverPanics)
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword: "explode",
+			Func: func(_ []string, _ io.Writer) error {
+				panic("boom")
+			},
+		},
+	)
+	errBuffer := &bytes.Buffer{}
+	c.SetErrOutput(errBuffer)
+
+	err := c.Run("explode", "now")
+
+	if !errors.Is(err, ErrPanic) || err.Error() != "the command panicked: boom" {
+		t.Errorf("the `Run` method did not return an error wrapping ErrPanic for a panicking command, got %v", err)
+	}
+
+	msg := errBuffer.String()
+	prefix := `panic in "explode": boom (crash report written to `
+	if !strings.HasPrefix(msg, prefix) || !strings.HasSuffix(msg, ")\n") {
+		t.Fatalf("the `Run` method printed an unexpected message for the panic: %q", msg)
+	}
+
+	report := strings.TrimSuffix(strings.TrimPrefix(msg, prefix), ")\n")
+	defer os.Remove(report)
+	bs, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("the crash report could not be read: %s", err)
+	}
+
+	for _, expect := range []string{"command: explode\n", "args: [\"now\"]\n", "panic: boom\n", "goroutine"} {
+		if !strings.Contains(string(bs), expect) {
+			t.Errorf("the crash report did not contain %q", expect)
+		}
+	}
+}
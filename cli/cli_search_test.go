@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
+)
+
+func TestCLI_Search(t *testing.T) {
+	c := New()
+	_ = c.SetOptions(options.BuiltinHelp)
+	_ = c.SetCommands(
+		lime.Command{
+			Keyword:     "deploy",
+			Description: "Ships the application to a server",
+		},
+		lime.Command{
+			Keyword:     "server",
+			Description: "Manages servers",
+			Commands: []lime.Command{
+				{
+					Keyword:     "restart",
+					Description: "Restarts a server",
+					Usage:       []lime.Usage{{Example: "myCli server restart web1", Explanation: "Restarts web1 after a deploy"}},
+				},
+			},
+		},
+		lime.Command{
+			Keyword:     "logs",
+			Aliases:     []string{"journal"},
+			Description: "Shows the logs",
+			Help:        "Useful after a deploy",
+		},
+		lime.Command{
+			Keyword:     "debug",
+			Description: "Dumps the state of the server",
+			Hidden:      true,
+		},
+	)
+	buffer := &bytes.Buffer{}
+	c.SetOutput(buffer)
+
+	// Ensure the commands are ranked by how well they match the query
+	{
+		cases := []struct {
+			query  string
+			expect []string
+		}{
+			{"deploy", []string{"deploy", "logs", "server restart"}},
+			{"SERVER", []string{"server", "server restart", "deploy"}},
+			{"restart server", []string{"server restart", "server", "deploy"}},
+			{"JOURNAL", []string{"logs"}},
+			{"bogus", []string{}},
+			{"", []string{}},
+		}
+		for _, tc := range cases {
+			paths := make([]string, 0)
+			for _, r := range c.Search(tc.query) {
+				paths = append(paths, strings.Join(r.Path, " "))
+			}
+			if strings.Join(paths, ", ") != strings.Join(tc.expect, ", ") {
+				t.Errorf("\nExpected results for %q: \n%v\nBut Got:\n%v\n", tc.query, tc.expect, paths)
+			}
+		}
+	}
+
+	// Ensure the help subcommand can search the commands
+	{
+		if err := c.Run("help", "search", "restart"); err != nil {
+			t.Errorf("the `Run` method returned an error for a search: %s", err)
+		}
+
+		if expect := "server restart   Restarts a server\n"; buffer.String() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, buffer.String())
+		}
+	}
+
+	// Ensure a search which finds nothing returns an error
+	if err := c.Run("help", "search", "bogus"); err != errNoResults {
+		t.Errorf("the `Run` method did not return errNoResults for a fruitless search, got %v", err)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	s := highlight("Restarts a server", []string{"server", "RESTART"})
+	if expect := "\033[1mRestart\033[0ms a \033[1mserver\033[0m"; s != expect {
+		t.Errorf("\nExpected: \n%q\nBut Got:\n%q\n", expect, s)
+	}
+}
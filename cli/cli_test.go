@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/dotvezz/lime"
@@ -105,3 +108,134 @@ func TestCLI_SetExitWord(t *testing.T) {
 		t.Error("the `SetExitWord` method did not save the exit word")
 	}
 }
+
+func TestCLI_SetPromptTemplate(t *testing.T) {
+	c := New()
+	err := c.SetPromptTemplate(`{{with .Vars.env}}[{{.}}] {{end}}{{.Context}}{{if .Failed}} (err){{end}}> `)
+	if err != nil {
+		t.Fatalf("the `SetPromptTemplate` method returned an error for a valid template: %s", err)
+	}
+
+	sh := newShell(*c)
+	if p := sh.prompt(); p != "> " {
+		t.Errorf("the prompt template rendered %q for a fresh session", p)
+	}
+
+	sh.session.SetVar("env", "prod")
+	sh.session.SetLastErr(errors.New("failed"))
+	sh.path = []string{"tell"}
+	if p := sh.prompt(); p != "[prod] tell (err)> " {
+		t.Errorf("the prompt template rendered %q after a failing command", p)
+	}
+
+	if err := c.SetPromptTemplate(`{{color "red" "x"}}`); err != nil {
+		t.Fatal(err)
+	}
+	if p := newShell(*c).prompt(); p != "\033[31mx\033[0m" {
+		t.Errorf("the prompt template did not render the color, got %q", p)
+	}
+
+	if err := c.SetPromptTemplate(`{{.Missing`); err == nil {
+		t.Error("the `SetPromptTemplate` method did not return an error for an invalid template")
+	}
+}
+
+func TestCLI_SetPromptFunc(t *testing.T) {
+	c := New()
+	c.SetName("myCli")
+	c.SetPromptFunc(func(data PromptData) string {
+		return data.Name + "$ "
+	})
+
+	if p := newShell(*c).prompt(); p != "myCli$ " {
+		t.Errorf("the `SetPromptFunc` method did not set the prompt function, got %q", p)
+	}
+}
+
+func TestCLI_SetVersion(t *testing.T) {
+	c := New()
+	c.SetVersion("1.2.3")
+	if c.version != "1.2.3" {
+		t.Error("the `SetVersion` method did not save the version")
+	}
+}
+
+func TestCLI_Banner(t *testing.T) {
+	commands := []lime.Command{
+		{
+			Keyword: "tell",
+			Commands: []lime.Command{
+				{Keyword: "lie"},
+				{Keyword: "truth"},
+			},
+		},
+		{
+			Keyword: "repeat",
+			Usage:   []lime.Usage{{Example: "x repeat fox", Explanation: "outputs fox"}},
+		},
+	}
+
+	// Ensure one-character names are shown
+	{
+		c := New()
+		c.SetName("x")
+		if b := c.banner(); b != "entering interactive mode for x\n" {
+			t.Errorf("the banner did not show a one-character name, got %q", b)
+		}
+	}
+
+	// Ensure the banner can be suppressed, or show a tip
+	{
+		c := New()
+		_ = c.SetCommands(commands...)
+		_ = c.SetOptions(options.NoBanner)
+		if b := c.banner(); b != "" {
+			t.Errorf("the NoBanner option did not suppress the banner, got %q", b)
+		}
+
+		c = New()
+		_ = c.SetCommands(commands...)
+		_ = c.SetOptions(options.BannerTips)
+		if b := c.banner(); b != "entering interactive mode\ntip: x repeat fox - outputs fox\n" {
+			t.Errorf("the BannerTips option did not add a tip, got %q", b)
+		}
+	}
+
+	// Ensure the tip is picked from the tip source
+	{
+		tipCommands := append(commands, lime.Command{
+			Keyword: "greet",
+			Usage: []lime.Usage{
+				{Example: "x greet", Explanation: "greets the world"},
+				{Example: "x greet John", Explanation: "greets John"},
+			},
+		})
+		expect := tipCommands[1].Usage[0]
+		if i := rand.New(rand.NewSource(42)).Intn(3); i > 0 {
+			expect = tipCommands[2].Usage[i-1]
+		}
+
+		c := New()
+		_ = c.SetCommands(tipCommands...)
+		_ = c.SetOptions(options.BannerTips)
+		c.SetTipSource(rand.NewSource(42))
+		if b := c.banner(); b != fmt.Sprintf("entering interactive mode\ntip: %s - %s\n", expect.Example, expect.Explanation) {
+			t.Errorf("the banner did not pick the tip from the tip source, got %q", b)
+		}
+	}
+
+	// Ensure a banner template has access to the name, version and command count
+	{
+		c := New()
+		c.SetName("x")
+		c.SetVersion("1.2.3")
+		_ = c.SetCommands(commands...)
+		err := c.SetBannerTemplate(`{{.Name}} {{.Version}} ({{.CommandCount}} commands){{with .Tip}} {{.Example}}{{end}}`)
+		if err != nil {
+			t.Fatalf("the `SetBannerTemplate` method returned an error for a valid template: %s", err)
+		}
+		if b := c.banner(); b != "x 1.2.3 (4 commands) x repeat fox" {
+			t.Errorf("the banner template rendered %q", b)
+		}
+	}
+}
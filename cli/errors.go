@@ -1,6 +1,12 @@
 package cli
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
 
 // errNoMatch is returned when Lime was unable to find a `lime.Command` matching the args/input
 var errNoMatch = errors.New("no matching command found")
@@ -11,11 +17,34 @@ var errNoFunc = errors.New("no function for command")
 // errInvalidOption is returned when the option given to `CLI.SetOptions` is not a power of 2 (because `lime.Options` is a bit mask)
 var errInvalidOption = errors.New("an invalid option value was given")
 
+// errFlagsWithoutContext is returned when a `lime.Command` declares or inherits flags, but only has a `lime.Func`,
+// which has no way to read them
+var errFlagsWithoutContext = errors.New("a command with flags needs a ContextFunc to read them")
+
+// errAliasCollision is returned when an alias of a `lime.Command` is also the keyword or an alias of one of its siblings
+var errAliasCollision = errors.New("the alias is already used by another command")
+
 // errNoInput is returned when Lime was unable to find args/input to use
 var errNoInput = errors.New("no command given")
 
-// errNoHelp is returned when the `Help` property is needed but not present.
-var errNoHelp = errors.New("no help provided for this command")
+// errNoResults is returned when a search finds no matching `lime.Command`
+var errNoResults = errors.New("no commands found")
+
+// ErrPanic is wrapped in the error returned when a panic was recovered while running a command, so that callers can
+// check for it with `errors.Is`
+var ErrPanic = errors.New("the command panicked")
+
+// usageError is returned when the args given to a `lime.Command` do not fit its declared `lime.Argument`s
+type usageError struct {
+	command *lime.Command
+	err     error
+	// The usage text rendered for the command, or empty to show its synopsis
+	usage string
+}
 
-// errNoHelp is returned when the `Help` property is needed but not present.
-var errNoUsage = errors.New("no usage provided for this command")
+func (e *usageError) Error() string {
+	if len(e.usage) > 0 {
+		return fmt.Sprintf("%s\n%s", e.err, strings.TrimRight(e.usage, "\n"))
+	}
+	return fmt.Sprintf("%s\nusage: %s", e.err, e.command.Synopsis(nil))
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
+
+// parseFlags separates the flags declared by a `lime.Command`, along with the flags it inherits, from its positional
+// args. Everything after a `--` arg, as well as any negative number, is treated as positional. Commands which declare
+// and inherit no flags receive their args untouched.
+func parseFlags(c *lime.Command, inherited []lime.Flag, args []string) (lime.Flags, []string, error) {
+	flags := lime.Flags{}
+	declared := append(append([]lime.Flag{}, c.Flags...), inherited...)
+	if len(declared) == 0 {
+		return flags, args, nil
+	}
+
+	for i := len(declared) - 1; i >= 0; i-- {
+		flags[declared[i].Name] = declared[i].Default
+	}
+
+	given := make(map[string]bool)
+	positional := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		// Negative numbers are positional values, not flags
+		if _, err := strconv.ParseFloat(arg, 64); len(arg) < 2 || arg[0] != '-' || err == nil {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value, hasValue = name[:j], name[j+1:], true
+		}
+
+		f := findFlag(declared, name)
+		if f == nil {
+			return nil, nil, &usageError{command: c, err: &lime.FlagError{Name: name, Reason: "unknown flag"}}
+		}
+
+		if !hasValue {
+			if f.Bool {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+			} else {
+				return nil, nil, &usageError{command: c, err: &lime.FlagError{Name: name, Reason: "a value is required"}}
+			}
+		}
+
+		flags[name] = value
+		given[name] = true
+	}
+
+	for _, f := range declared {
+		if f.Required && !given[f.Name] {
+			return nil, nil, &usageError{command: c, err: &lime.FlagError{Name: f.Name, Reason: "the flag is required"}}
+		}
+	}
+
+	return flags, positional, nil
+}
+
+// inheritedFlags collects the persistent flags of the commands along a trail, other than the last, starting with the
+// nearest. A flag declared nearer to the last command takes precedence over one of the same name declared further up.
+func inheritedFlags(trail []*lime.Command) []lime.Flag {
+	flags := make([]lime.Flag, 0)
+	for i := len(trail) - 2; i >= 0; i-- {
+		for _, f := range trail[i].Flags {
+			if f.Persistent {
+				flags = append(flags, f)
+			}
+		}
+	}
+	return flags
+}
+
+// findFlag returns the `lime.Flag` with the given name, or nil if there is none
+func findFlag(flags []lime.Flag, name string) *lime.Flag {
+	for i := range flags {
+		if flags[i].Name == name {
+			return &flags[i]
+		}
+	}
+	return nil
+}
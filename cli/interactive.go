@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dotvezz/lime"
+	"github.com/dotvezz/lime/options"
+)
+
+const (
+	parentWord   = ".."
+	absolutePath = "/"
+)
+
+// isTerminal returns true if an input or output stream is a terminal, rather than a pipe or a file
+var isTerminal = func(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	return ok && terminal(f)
+}
+
+// shell holds the state of an interactive session
+type shell struct {
+	cli     CLI
+	session *lime.Session
+	ctx     context.Context
+	// The args of the command namespace the session has entered, see `shell.run`
+	path []string
+	// The aliases defined in the session, see `builtinAlias`
+	aliases map[string]string
+	// The number of scripts being sourced, see `shell.source`
+	sourceDepth int
+	// The prompt of the session, which takes precedence over the CLI's prompt, see `builtinPrompt`
+	promptFunc PromptFunc
+}
+
+// newShell creates a shell with a new `lime.Session`
+func newShell(cli CLI) *shell {
+	session := lime.NewSession()
+	return &shell{
+		cli:     cli,
+		session: session,
+		ctx:     lime.WithSession(context.Background(), session),
+		path:    make([]string, 0),
+		aliases: make(map[string]string),
+	}
+}
+
+// interactive launches the program in interactive mode, after sourcing the rc file at the given path, if any.
+// When the input is not a terminal, it runs in batch mode instead, without the banner, prompts or rc file, and writes
+// errors to the error output stream. Batch mode stops at the first error if the StopOnError option is set.
+// Returns the error which stopped batch mode, if any.
+func (cli CLI) interactive(rc string, rcChosen bool) error {
+	terminal := isTerminal(cli.in)
+	errOut := cli.out
+	if !terminal {
+		errOut = cli.err
+	}
+
+	if terminal {
+		_, err := fmt.Fprint(cli.out, cli.banner())
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	sh := newShell(cli)
+	defer func() {
+		if err := cli.exit(); err != nil {
+			_, _ = fmt.Fprintln(errOut, err)
+		}
+		if err := sh.session.Close(); err != nil {
+			_, _ = fmt.Fprintln(errOut, err)
+		}
+	}()
+	if err := cli.start(); err != nil {
+		_, _ = fmt.Fprintln(errOut, err)
+		return err
+	}
+	if terminal {
+		if err := sh.sourceRC(rc, rcChosen); err != nil {
+			_, _ = fmt.Fprintln(errOut, err)
+		}
+	}
+
+	scanner := bufio.NewScanner(cli.in)
+	for {
+		if terminal {
+			_, err := fmt.Fprint(cli.out, sh.prompt())
+			if err != nil {
+				panic(err)
+			}
+		}
+		if !scanner.Scan() {
+			// End the line of the prompt when a terminal user ends the input, such as with Ctrl-D
+			if terminal {
+				_, _ = fmt.Fprintln(cli.out)
+			}
+			break
+		}
+		input := scanner.Text()
+		if input == cli.exitWord {
+			break
+		}
+		if err := sh.execute(input); err != nil {
+			_, _ = fmt.Fprintln(errOut, err)
+			if !terminal && cli.options&options.StopOnError > 0 {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// prompt renders the prompt for the next line of input
+func (sh *shell) prompt() string {
+	lastErr := sh.session.LastErr()
+	data := PromptData{
+		Name:    sh.cli.name,
+		Prompt:  sh.cli.prompt,
+		Path:    append([]string{}, sh.path...),
+		Context: strings.Join(sh.path, argumentSeparator),
+		LastErr: lastErr,
+		Failed:  lastErr != nil,
+		Vars:    sh.session.Vars(),
+		Time:    time.Now(),
+		Session: sh.session,
+	}
+	if sh.promptFunc != nil {
+		return sh.promptFunc(data)
+	}
+	if sh.cli.promptFunc != nil {
+		return sh.cli.promptFunc(data)
+	}
+	return defaultPrompt(data)
+}
+
+// execute runs a single line of input, recording it in the history of the session
+func (sh *shell) execute(input string) error {
+	if len(strings.TrimSpace(input)) == 0 {
+		return nil
+	}
+	sh.session.AddHistory(input)
+	err := sh.run(input)
+	sh.session.SetLastErr(err)
+	return err
+}
+
+// run runs a single line of input.
+// Input is matched relative to the command namespace the session has entered, unless it starts with a slash. Matching
+// a command which has nested commands but no function enters its namespace, and `..` leaves it. Input which matches no
+// command may invoke an enabled builtin instead.
+func (sh *shell) run(input string) error {
+	if len(strings.TrimSpace(input)) == 0 {
+		return nil
+	}
+	input = sh.expandAlias(input)
+
+	words := strings.Split(input, argumentSeparator)
+	args := words
+	switch {
+	case input == parentWord:
+		if len(sh.path) > 0 {
+			sh.path = sh.path[:len(sh.path)-1]
+		}
+		return nil
+	case input == absolutePath:
+		sh.path = sh.path[:0]
+		return nil
+	case strings.HasPrefix(input, absolutePath):
+		args = append([]string{strings.TrimPrefix(words[0], absolutePath)}, words[1:]...)
+	default:
+		args = append(append([]string{}, sh.path...), words...)
+	}
+
+	r, err := match(sh.cli.commands, args)
+	if err != nil {
+		if b, ok := sh.builtin(words[0]); ok {
+			return b.run(sh, words[1:])
+		}
+		return err
+	}
+
+	if r.depth() == len(args) && r.command.Func == nil && r.command.ContextFunc == nil && len(r.command.Commands) > 0 {
+		sh.path = args
+		return nil
+	}
+
+	return sh.cli.exec(sh.ctx, r, args)
+}
@@ -1,25 +1,73 @@
 package cli
 
 import (
+	"strings"
+
 	"github.com/dotvezz/lime"
 )
 
+// route is the result of matching a set of arguments to a `lime.Command`
+type route struct {
+	// The matched command
+	command *lime.Command
+	// The commands along the way to the matched command, starting at the top level and ending with the matched command
+	trail []*lime.Command
+	// The values matched by any placeholder keywords along the way
+	params lime.Params
+}
+
+// depth returns the number of arguments consumed by the route
+func (r *route) depth() int {
+	return len(r.trail)
+}
+
 // match finds a matching command for a given set of arguments.
-// Also returns the nesting depth of the matched command.
-func match(commands []lime.Command, args []string, depth int) (*lime.Command, int, error) {
+func match(commands []lime.Command, args []string) (*route, error) {
+	r := &route{params: lime.Params{}}
+	return r, r.match(commands, args)
+}
 
+func (r *route) match(commands []lime.Command, args []string) error {
 	if len(args) > 0 {
-		var c *lime.Command
+		// Literal keywords take precedence over aliases, which take precedence over placeholders
+		for i := range commands {
+			if commands[i].Keyword == args[0] {
+				return r.descend(&commands[i], args)
+			}
+		}
 		for i := range commands {
-			c = &commands[i]
-			if c.Keyword == args[0] {
-				if len(args) > 1 && len(c.Commands) > 0 && len(args) > 1 {
-					return match(c.Commands, args[1:], depth+1)
+			for _, alias := range commands[i].Aliases {
+				if alias == args[0] {
+					return r.descend(&commands[i], args)
 				}
-				return c, depth, nil
+			}
+		}
+		for i := range commands {
+			if name, ok := placeholder(commands[i].Keyword); ok {
+				r.params[name] = args[0]
+				return r.descend(&commands[i], args)
 			}
 		}
 	}
 
-	return nil, depth, errNoMatch
+	return errNoMatch
+}
+
+// descend continues matching into the nested commands of c, or settles on c if there is nothing left to match
+func (r *route) descend(c *lime.Command, args []string) error {
+	r.command = c
+	r.trail = append(r.trail, c)
+	if len(args) > 1 && len(c.Commands) > 0 {
+		return r.match(c.Commands, args[1:])
+	}
+	return nil
+}
+
+// placeholder returns the name of a placeholder keyword such as `<id>`. Returns false if the keyword is not a
+// placeholder.
+func placeholder(keyword string) (string, bool) {
+	if len(keyword) > 2 && strings.HasPrefix(keyword, "<") && strings.HasSuffix(keyword, ">") {
+		return keyword[1 : len(keyword)-1], true
+	}
+	return "", false
 }
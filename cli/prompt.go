@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dotvezz/lime"
+)
+
+// ansiCodes holds the ANSI escape codes available to prompt templates through the `color` function
+var ansiCodes = map[string]string{
+	"reset":   "\033[0m",
+	"bold":    "\033[1m",
+	"dim":     "\033[2m",
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+}
+
+// templateFuncs are the functions available to prompt templates
+var templateFuncs = template.FuncMap{
+	// color wraps text in the ANSI escape code of the named color or style, such as `{{color "red" .Context}}`
+	"color": func(name string, text interface{}) string {
+		code, ok := ansiCodes[name]
+		if !ok {
+			return fmt.Sprint(text)
+		}
+		return fmt.Sprintf("%s%v%s", code, text, ansiCodes["reset"])
+	},
+	// join joins a slice of strings with spaces, such as `{{join .Path}}`
+	"join": func(s []string) string {
+		return strings.Join(s, argumentSeparator)
+	},
+	// wrap wraps text to a width, indenting each line after the first, such as `{{wrap .Width 4 .Command.Help}}`
+	"wrap": func(width, indent int, text string) string {
+		return wrapText(text, width, indent)
+	},
+}
+
+// PromptData is the information available when rendering the prompt of the interactive mode
+type PromptData struct {
+	// The name of the CLI
+	Name string
+	// The static prompt of the CLI
+	Prompt string
+	// The args of the command namespace the session has entered
+	Path []string
+	// The Path, joined with spaces
+	Context string
+	// The error returned by the most recent command, or nil if it succeeded
+	LastErr error
+	// Whether the most recent command returned an error
+	Failed bool
+	// The variables of the session
+	Vars map[string]string
+	// The time the prompt is rendered
+	Time time.Time
+	// The session itself
+	Session *lime.Session
+}
+
+// PromptFunc is the signature of a function which renders the prompt of the interactive mode
+type PromptFunc func(data PromptData) string
+
+// SetPromptFunc takes a function to render the prompt before every line in interactive mode, in place of the static
+// prompt
+func (cli *CLI) SetPromptFunc(f PromptFunc) {
+	cli.promptFunc = f
+}
+
+// SetPromptTemplate takes a text/template to render the prompt before every line in interactive mode, in place of the
+// static prompt. The template is executed with a PromptData, and can use the `color` and `join` functions, such as
+// `{{with .Vars.env}}[{{.}}] {{end}}{{color "cyan" .Context}}{{if .Failed}} (err){{end}}> `.
+// Returns an error if the template can not be parsed.
+func (cli *CLI) SetPromptTemplate(text string) error {
+	f, err := promptTemplate(text)
+	if err != nil {
+		return err
+	}
+	cli.promptFunc = f
+	return nil
+}
+
+// promptTemplate parses a prompt template into a PromptFunc. The PromptFunc renders a template which fails to execute
+// as the default prompt.
+func promptTemplate(text string) (PromptFunc, error) {
+	t, err := template.New("prompt").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return func(data PromptData) string {
+		sb := new(strings.Builder)
+		if err := t.Execute(sb, data); err != nil {
+			return defaultPrompt(data)
+		}
+		return sb.String()
+	}, nil
+}
+
+// defaultPrompt renders the static prompt, prefixed by the namespace the session has entered
+func defaultPrompt(data PromptData) string {
+	return fmt.Sprintf("%s%s ", data.Context, data.Prompt)
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotvezz/lime/options"
+)
+
+const (
+	rcFileName = "rc.lime"
+	noRCFlag   = "--norc"
+	rcFlag     = "--rc"
+)
+
+// runControl finds the rc file to source when interactive mode starts, and removes the flags which choose it from the
+// start of the args. By default the rc file is `rc.lime` in the CLI's directory under the user's config directory, such
+// as `~/.config/myCli/rc.lime`. The `--norc` flag skips the rc file, and `--rc <file>` points at a different one.
+// Returns an empty path if no rc file should be sourced, and whether the rc file was chosen by a flag.
+func (cli CLI) runControl(args []string) (string, bool, []string) {
+	if cli.options&options.RunControl == 0 {
+		return "", false, args
+	}
+
+	path, chosen := "", false
+	if dir, err := os.UserConfigDir(); err == nil && len(cli.name) > 0 {
+		path = filepath.Join(dir, cli.name, rcFileName)
+	}
+
+	for len(args) > 0 {
+		switch {
+		case args[0] == noRCFlag:
+			path, chosen = "", true
+			args = args[1:]
+		case args[0] == rcFlag && len(args) > 1:
+			path, chosen = args[1], true
+			args = args[2:]
+		case strings.HasPrefix(args[0], rcFlag+"="):
+			path, chosen = strings.TrimPrefix(args[0], rcFlag+"="), true
+			args = args[1:]
+		default:
+			return path, chosen, args
+		}
+	}
+
+	return path, chosen, args
+}
+
+// sourceRC sources the rc file at the given path. A missing rc file is only an error if it was chosen by a flag.
+func (sh *shell) sourceRC(path string, chosen bool) error {
+	if len(path) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) && !chosen {
+		return nil
+	}
+	return sh.source(path)
+}
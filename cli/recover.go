@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/dotvezz/lime"
+)
+
+// recoverPanics returns middleware which recovers from a panic in the rest of the chain. It writes a crash report to a
+// temporary file, prints a concise message to the error stream, and returns an error wrapping ErrPanic in place of the
+// panic.
+func (cli CLI) recoverPanics(next lime.Handler) lime.Handler {
+	return func(ctx context.Context, inv lime.Invocation) (err error) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			stack := debug.Stack()
+			path := strings.Join(inv.Path, argumentSeparator)
+			report, reportErr := cli.writeCrashReport(p, stack, inv)
+			if reportErr != nil {
+				_, _ = fmt.Fprintf(inv.Err, "panic in %q: %v (could not write crash report: %s)\n", path, p, reportErr)
+			} else {
+				_, _ = fmt.Fprintf(inv.Err, "panic in %q: %v (crash report written to %s)\n", path, p, report)
+			}
+			err = fmt.Errorf("%w: %v", ErrPanic, p)
+		}()
+		return next(ctx, inv)
+	}
+}
+
+// writeCrashReport writes the details of a recovered panic to a temporary file, and returns the name of the file
+func (cli CLI) writeCrashReport(p interface{}, stack []byte, inv lime.Invocation) (string, error) {
+	prefix := cli.name
+	if len(prefix) == 0 {
+		prefix = "lime"
+	}
+	f, err := os.CreateTemp("", prefix+"-crash-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := crashReport(f, p, stack, inv); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// crashReport renders the details of a recovered panic
+func crashReport(w io.Writer, p interface{}, stack []byte, inv lime.Invocation) error {
+	sb := new(strings.Builder)
+	_, _ = fmt.Fprintf(sb, "time: %s\n", time.Now().Format(time.RFC3339))
+	_, _ = fmt.Fprintf(sb, "command: %s\n", strings.Join(inv.Path, argumentSeparator))
+	_, _ = fmt.Fprintf(sb, "args: %q\n", inv.Args)
+	_, _ = fmt.Fprintf(sb, "panic: %v\n\n", p)
+	_, _ = fmt.Fprintf(sb, "%s\n", stack)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		_, _ = fmt.Fprintf(sb, "build info:\n%s", info)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
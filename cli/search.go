@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
+
+// searchKeyword follows the help subcommand or builtin to search the commands, such as `help search deploy`
+const searchKeyword = "search"
+
+// The weight of a match of a term in each part of a command, used to rank search results
+const (
+	keywordWeight     = 8
+	descriptionWeight = 4
+	helpWeight        = 2
+	usageWeight       = 1
+)
+
+// SearchResult is a command found by CLI.Search
+type SearchResult struct {
+	// The keywords leading to the command, including its own
+	Path []string
+	// The command itself
+	Command *lime.Command
+	// How well the command matches the query, higher is better
+	Score int
+	// The terms of the query which the command matches
+	Terms []string
+}
+
+// Search finds the commands whose keywords, aliases, descriptions, help or usage examples contain any of the words of
+// the query, ignoring case. Hidden commands are left out. The results are ranked by the number of terms they match, and
+// then by where the terms were matched, with keywords and aliases ranking above descriptions, help and usage examples
+// in that order.
+func (cli CLI) Search(query string) []SearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	results := make([]SearchResult, 0)
+	if len(terms) == 0 {
+		return results
+	}
+
+	for _, e := range helpEntries(cli.commands, make([]string, 0), "") {
+		r := SearchResult{Path: e.Path, Command: e.Command}
+		for _, term := range terms {
+			score := searchScore(e.Command, term)
+			if score > 0 {
+				r.Score += score
+				r.Terms = append(r.Terms, term)
+			}
+		}
+		if len(r.Terms) > 0 {
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if len(results[i].Terms) != len(results[j].Terms) {
+			return len(results[i].Terms) > len(results[j].Terms)
+		}
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// searchScore weighs where a single term is found in a command, returning 0 if it is not found
+func searchScore(c *lime.Command, term string) int {
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), term)
+	}
+
+	score := 0
+	if contains(c.Keyword) {
+		score += keywordWeight
+	} else {
+		for _, alias := range c.Aliases {
+			if contains(alias) {
+				score += keywordWeight
+				break
+			}
+		}
+	}
+	if contains(c.Description) {
+		score += descriptionWeight
+	}
+	if contains(c.Help) {
+		score += helpWeight
+	}
+	for _, u := range c.Usage {
+		if contains(u.Example) || contains(u.Explanation) {
+			score += usageWeight
+			break
+		}
+	}
+	return score
+}
+
+// searchHelp renders the results of a search in aligned columns, with the matched terms highlighted if the output is a
+// terminal. Returns errNoResults if nothing matches the query.
+func (cli CLI) searchHelp(query string) (string, error) {
+	results := cli.Search(query)
+	if len(results) == 0 {
+		return "", errNoResults
+	}
+
+	rows := make([][2]string, len(results))
+	for i, r := range results {
+		rows[i] = [2]string{strings.Join(r.Path, argumentSeparator), r.Command.Description}
+	}
+	sb := new(strings.Builder)
+	writeColumns(sb, rows, "", terminalWidth(cli.out))
+
+	if !isTerminal(cli.out) {
+		return sb.String(), nil
+	}
+	return highlight(sb.String(), strings.Fields(query)), nil
+}
+
+// highlight wraps each occurrence of the terms in the text in ANSI bold, ignoring case
+func highlight(text string, terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		return ansiCodes["bold"] + match + ansiCodes["reset"]
+	})
+}
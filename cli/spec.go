@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dotvezz/lime/spec"
+)
+
+// specKeyword invokes the hidden command which writes the command tree as JSON, when the SpecCommand option is set
+const specKeyword = "__commands"
+
+// Spec describes the CLI and its whole command tree, including hidden commands
+func (cli CLI) Spec() spec.Spec {
+	return spec.New(cli.name, cli.version, cli.commands)
+}
+
+// WriteSpec writes the Spec of the CLI to w as indented JSON
+func (cli CLI) WriteSpec(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cli.Spec())
+}
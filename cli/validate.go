@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
+
+// validateCommands checks a tree of commands before they are stored in the CLI. A command which declares or inherits
+// flags must have a ContextFunc, or no function at all, since the flags are separated from the args given to a Func.
+// The aliases of a command must not be the keyword or an alias of any of its siblings.
+func validateCommands(commands []lime.Command, path []string, inherited []lime.Flag) error {
+	if err := validateAliases(commands, path); err != nil {
+		return err
+	}
+	for i := range commands {
+		c := &commands[i]
+		p := append(append([]string{}, path...), c.Keyword)
+		if c.Func != nil && c.ContextFunc == nil && (len(c.Flags) > 0 || len(inherited) > 0) {
+			return fmt.Errorf("%q: %w", strings.Join(p, argumentSeparator), errFlagsWithoutContext)
+		}
+
+		nested := inherited
+		for _, f := range c.Flags {
+			if f.Persistent {
+				nested = append(append([]lime.Flag{}, nested...), f)
+			}
+		}
+		if err := validateCommands(c.Commands, p, nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAliases checks that no alias of a command is also the keyword or an alias of one of its siblings, since only
+// one of them could ever be matched
+func validateAliases(commands []lime.Command, path []string) error {
+	keywords := make(map[string]bool, len(commands))
+	for i := range commands {
+		keywords[commands[i].Keyword] = true
+	}
+
+	aliases := make(map[string]bool)
+	for i := range commands {
+		for _, alias := range commands[i].Aliases {
+			if keywords[alias] || aliases[alias] {
+				p := append(append([]string{}, path...), commands[i].Keyword)
+				return fmt.Errorf("%q: alias %q: %w", strings.Join(p, argumentSeparator), alias, errAliasCollision)
+			}
+			aliases[alias] = true
+		}
+	}
+	return nil
+}
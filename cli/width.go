@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultWidth is the width of help which is not written to a terminal, in columns
+	defaultWidth = 80
+	// minWrapWidth is the narrowest text is wrapped to, however little room is left beside a column
+	minWrapWidth = 20
+)
+
+// terminalWidth returns the width of the output stream in columns. Returns defaultWidth if the output is not a
+// terminal, so that piped help does not depend on the environment. Otherwise the `COLUMNS` environment variable takes
+// precedence, followed by the size of the terminal window.
+func terminalWidth(out io.Writer) int {
+	if !isTerminal(out) {
+		return defaultWidth
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := out.(*os.File); ok {
+		if n := windowWidth(f); n > 0 {
+			return n
+		}
+	}
+	return defaultWidth
+}
+
+// wrapText wraps text at word boundaries to fit the width, indenting each line after the first. The first line is
+// expected to already start at the indent. Line breaks in the text are kept, and words too long for a line are left
+// whole.
+func wrapText(text string, width, indent int) string {
+	room := width - indent
+	if room < minWrapWidth {
+		room = minWrapWidth
+	}
+	pad := "\n" + strings.Repeat(" ", indent)
+
+	sb := new(strings.Builder)
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			sb.WriteString(pad)
+		}
+		n := 0
+		for _, word := range strings.Fields(line) {
+			if n > 0 && n+1+len(word) > room {
+				sb.WriteString(pad)
+				n = 0
+			} else if n > 0 {
+				sb.WriteString(" ")
+				n++
+			}
+			sb.WriteString(word)
+			n += len(word)
+		}
+	}
+	return sb.String()
+}
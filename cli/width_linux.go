@@ -0,0 +1,29 @@
+//go:build linux
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// windowWidth asks the terminal for the width of its window through an ioctl. Returns 0 if f is not a terminal.
+func windowWidth(f *os.File) int {
+	var size struct {
+		rows, cols, x, y uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0
+	}
+	return int(size.cols)
+}
+
+// terminal asks f for its terminal attributes through an ioctl, which only succeeds if f is a terminal. Other character
+// devices, such as /dev/null, are not terminals.
+func terminal(f *os.File) bool {
+	var attrs syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(&attrs)))
+	return errno == 0
+}
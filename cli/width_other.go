@@ -0,0 +1,16 @@
+//go:build !linux
+
+package cli
+
+import "os"
+
+// windowWidth is only supported on Linux, elsewhere the width comes from the `COLUMNS` environment variable
+func windowWidth(_ *os.File) int {
+	return 0
+}
+
+// terminal treats any character device as a terminal, since asking for terminal attributes is only supported on Linux
+func terminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,53 @@
+// Command limediff compares two versions of a lime CLI's command tree, as written by `myCli __commands` or
+// `cli.CLI.WriteSpec`, and lists the changes between them. It exits with status 1 if any change is breaking, so that
+// it can guard a release process.
+//
+//	limediff old.json new.json
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dotvezz/lime/spec"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: limediff <old.json> <new.json>")
+		os.Exit(2)
+	}
+
+	before, err := read(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	after, err := read(os.Args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	changes := spec.Diff(before, after)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	if spec.HasBreaking(changes) {
+		os.Exit(1)
+	}
+}
+
+// read reads a Spec from a JSON file
+func read(path string) (spec.Spec, error) {
+	var s spec.Spec
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,49 @@
+package lime
+
+import "context"
+
+// contextKey is the type of the keys lime uses to store values in a context.Context
+type contextKey int
+
+const (
+	paramsKey contextKey = iota
+	flagsKey
+	sessionKey
+)
+
+// WithParams returns a copy of ctx which carries the given Params
+func WithParams(ctx context.Context, params Params) context.Context {
+	return context.WithValue(ctx, paramsKey, params)
+}
+
+// ParamsFrom returns the Params carried by ctx. Returns an empty Params if ctx carries none.
+func ParamsFrom(ctx context.Context) Params {
+	if params, ok := ctx.Value(paramsKey).(Params); ok {
+		return params
+	}
+	return Params{}
+}
+
+// WithFlags returns a copy of ctx which carries the given Flags
+func WithFlags(ctx context.Context, flags Flags) context.Context {
+	return context.WithValue(ctx, flagsKey, flags)
+}
+
+// FlagsFrom returns the Flags carried by ctx. Returns an empty Flags if ctx carries none.
+func FlagsFrom(ctx context.Context) Flags {
+	if flags, ok := ctx.Value(flagsKey).(Flags); ok {
+		return flags
+	}
+	return Flags{}
+}
+
+// WithSession returns a copy of ctx which carries the given Session
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey, session)
+}
+
+// SessionFrom returns the Session carried by ctx. Returns nil if ctx carries none.
+func SessionFrom(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey).(*Session)
+	return session
+}
@@ -0,0 +1,41 @@
+// Package doc generates documentation, such as man pages, from a tree of `lime.Command`s.
+package doc
+
+import (
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
+
+// entry is a command along with the keywords leading to it, including the name of the CLI
+type entry struct {
+	path    []string
+	command *lime.Command
+}
+
+// walk visits each command in a tree which has a keyword and is not hidden, parents before their nested commands
+func walk(commands []lime.Command, path []string, visit func(e entry)) {
+	for i := range commands {
+		keyword := strings.Trim(commands[i].Keyword, " ")
+		if len(keyword) == 0 || commands[i].Hidden {
+			continue
+		}
+		p := append(append([]string{}, path...), keyword)
+		visit(entry{path: p, command: &commands[i]})
+		walk(commands[i].Commands, p, visit)
+	}
+}
+
+// documented returns true if a command gets a page of its own, because it can be run or has no nested commands
+func documented(c *lime.Command) bool {
+	return len(c.Commands) == 0 || c.Func != nil || c.ContextFunc != nil
+}
+
+// pageName returns the name of the page for the command at the given path, such as `myCli-user-id-delete`
+func pageName(path []string) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strings.Trim(p, "<>")
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,51 @@
+package doc
+
+import (
+	"io"
+
+	"github.com/dotvezz/lime"
+)
+
+var noop = func(_ []string, _ io.Writer) error {
+	return nil
+}
+
+var testCommands = []lime.Command{
+	{
+		Keyword:     "tell",
+		Description: "Makes statements",
+		Commands: []lime.Command{
+			{
+				Keyword:     "lie",
+				Description: "Makes a preset statement which is factually untrue.",
+				Func:        noop,
+			},
+		},
+	},
+	{
+		Keyword:     "repeat",
+		Aliases:     []string{"echo", "say"},
+		Description: "Repeats all the words after the command.",
+		Help:        "Words are separated by spaces.",
+		Flags: []lime.Flag{
+			{Name: "times", Description: "How many times to repeat", Default: "1"},
+			{Name: "loud", Description: "Shout the words", Bool: true},
+		},
+		Arguments: []lime.Argument{
+			{Name: "word", Description: "A word to repeat", Variadic: true},
+		},
+		Usage: []lime.Usage{
+			{Example: "myCli repeat the quick brown fox", Explanation: `outputs "[the quick brown fox]"`},
+		},
+		Func: noop,
+	},
+	{
+		Description: "no keyword",
+	},
+	{
+		Keyword:     "debug",
+		Description: "Dumps the internal state",
+		Hidden:      true,
+		Func:        noop,
+	},
+}
@@ -0,0 +1,216 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dotvezz/lime"
+)
+
+const defaultSection = "1"
+
+// ManHeader holds the metadata of generated man pages
+type ManHeader struct {
+	// The name of the CLI, used to name the pages and in their synopses
+	Name string
+	// A brief description of the CLI, used in the top-level page
+	Description string
+	// The section of the manual, "1" if empty
+	Section string
+	// The date shown in the page footers. Left out if zero, which keeps generated pages stable
+	Date time.Time
+	// The source of the pages shown in their footers, such as "myCli 1.2.3"
+	Source string
+	// The title of the manual shown in the page headers, such as "myCli Manual"
+	Manual string
+}
+
+// GenManTree writes a top-level man page for the CLI to dir, along with a page for each command which can be run or
+// has no nested commands. The pages are named after the keywords leading to their commands, such as `myCli-tell-lie.1`.
+func GenManTree(commands []lime.Command, header ManHeader, dir string) error {
+	header = header.withDefaults()
+	write := func(name string, gen func(w io.Writer) error) error {
+		f, err := os.Create(filepath.Join(dir, name+"."+header.Section))
+		if err != nil {
+			return err
+		}
+		if err := gen(f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	err := write(header.Name, func(w io.Writer) error {
+		return GenMan(w, commands, header)
+	})
+
+	walk(commands, []string{header.Name}, func(e entry) {
+		if err != nil || !documented(e.command) {
+			return
+		}
+		err = write(pageName(e.path), func(w io.Writer) error {
+			return GenCommandMan(w, e.path[1:], e.command, header)
+		})
+	})
+
+	return err
+}
+
+// GenMan writes the top-level man page for the CLI, which lists its commands
+func GenMan(w io.Writer, commands []lime.Command, header ManHeader) error {
+	header = header.withDefaults()
+	sb := new(strings.Builder)
+
+	writeTitle(sb, header.Name, header)
+	sb.WriteString(".SH NAME\n")
+	if len(header.Description) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s \\- %s\n", escape(header.Name), escape(header.Description))
+	} else {
+		_, _ = fmt.Fprintf(sb, "%s\n", escape(header.Name))
+	}
+	_, _ = fmt.Fprintf(sb, ".SH SYNOPSIS\n.B %s\n\\fIcommand\\fR [\\fIargs\\fR]\n", escape(header.Name))
+
+	pages := make([]string, 0)
+	sb.WriteString(".SH COMMANDS\n")
+	walk(commands, []string{header.Name}, func(e entry) {
+		if !documented(e.command) {
+			return
+		}
+		_, _ = fmt.Fprintf(sb, ".TP\n.B %s\n%s\n", escape(strings.Join(e.path[1:], " ")), escape(e.command.Description))
+		pages = append(pages, pageName(e.path))
+	})
+
+	writeSeeAlso(sb, pages, header)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenCommandMan writes the man page for a command, given the keywords leading to it
+func GenCommandMan(w io.Writer, path []string, c *lime.Command, header ManHeader) error {
+	header = header.withDefaults()
+	full := append([]string{header.Name}, path...)
+	sb := new(strings.Builder)
+
+	writeTitle(sb, pageName(full), header)
+	sb.WriteString(".SH NAME\n")
+	if len(c.Description) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s \\- %s\n", escape(pageName(full)), escape(c.Description))
+	} else {
+		_, _ = fmt.Fprintf(sb, "%s\n", escape(pageName(full)))
+	}
+
+	_, _ = fmt.Fprintf(sb, ".SH SYNOPSIS\n.B %s\n", escape(strings.Join(full, " ")))
+	synopsis := make([]string, 0, len(c.Flags)+len(c.Arguments))
+	for _, f := range c.Flags {
+		synopsis = append(synopsis, manFlag(f, true))
+	}
+	for _, a := range c.Arguments {
+		synopsis = append(synopsis, "\\fI"+escape(a.Synopsis())+"\\fR")
+	}
+	if len(synopsis) > 0 {
+		_, _ = fmt.Fprintln(sb, strings.Join(synopsis, " "))
+	}
+
+	if len(c.Help) > 0 {
+		_, _ = fmt.Fprintf(sb, ".SH DESCRIPTION\n%s\n", escape(c.Help))
+	}
+
+	if deprecation := c.Deprecation(); len(deprecation) > 0 {
+		_, _ = fmt.Fprintf(sb, ".SH DEPRECATED\n%s\n", escape(deprecation))
+	}
+
+	if len(c.Aliases) > 0 {
+		aliases := make([]string, len(c.Aliases))
+		for i, alias := range c.Aliases {
+			aliases[i] = "\\fB" + escape(alias) + "\\fR"
+		}
+		_, _ = fmt.Fprintf(sb, ".SH ALIASES\n%s\n", strings.Join(aliases, ", "))
+	}
+
+	if len(c.Flags) > 0 {
+		sb.WriteString(".SH OPTIONS\n")
+		for _, f := range c.Flags {
+			_, _ = fmt.Fprintf(sb, ".TP\n%s\n%s\n", manFlag(f, false), escape(f.Describe()))
+		}
+	}
+
+	if len(c.Arguments) > 0 {
+		sb.WriteString(".SH ARGUMENTS\n")
+		for _, a := range c.Arguments {
+			_, _ = fmt.Fprintf(sb, ".TP\n\\fI%s\\fR\n%s\n", escape(a.Name), escape(a.Describe()))
+		}
+	}
+
+	if len(c.Usage) > 0 {
+		sb.WriteString(".SH EXAMPLES\n")
+		for _, u := range c.Usage {
+			_, _ = fmt.Fprintf(sb, ".TP\n.B %s\n%s\n", escape(u.Example), escape(u.Explanation))
+		}
+	}
+
+	writeSeeAlso(sb, []string{header.Name}, header)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// withDefaults fills in the defaults of a ManHeader
+func (h ManHeader) withDefaults() ManHeader {
+	if len(h.Section) == 0 {
+		h.Section = defaultSection
+	}
+	return h
+}
+
+// writeTitle writes the title line of a page
+func writeTitle(sb *strings.Builder, name string, header ManHeader) {
+	date := ""
+	if !header.Date.IsZero() {
+		date = header.Date.Format("2006-01-02")
+	}
+	_, _ = fmt.Fprintf(sb, ".TH \"%s\" \"%s\" \"%s\" \"%s\" \"%s\"\n",
+		escape(strings.ToUpper(name)), escape(header.Section), date, escape(header.Source), escape(header.Manual))
+}
+
+// writeSeeAlso writes references to the given pages
+func writeSeeAlso(sb *strings.Builder, pages []string, header ManHeader) {
+	if len(pages) == 0 {
+		return
+	}
+	refs := make([]string, len(pages))
+	for i, page := range pages {
+		refs[i] = fmt.Sprintf("\\fB%s\\fR(%s)", escape(page), header.Section)
+	}
+	_, _ = fmt.Fprintf(sb, ".SH SEE ALSO\n%s\n", strings.Join(refs, ", "))
+}
+
+// manFlag renders a flag in roff, in brackets for a synopsis unless it is required
+func manFlag(f lime.Flag, synopsis bool) string {
+	s := fmt.Sprintf("\\fB\\-\\-%s\\fR", escape(f.Name))
+	if !f.Bool {
+		s += " \\fIvalue\\fR"
+	}
+	if synopsis && !f.Required {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// escape escapes text for roff, so that it is never read as a request or an escape sequence
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package doc
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGenCommandMan(t *testing.T) {
+	sb := new(strings.Builder)
+	header := ManHeader{Name: "myCli", Source: "myCli 1.0", Manual: "myCli Manual"}
+	if err := GenCommandMan(sb, []string{"repeat"}, &testCommands[1], header); err != nil {
+		t.Fatalf("the `GenCommandMan` function returned an error: %s", err)
+	}
+
+	expect := `.TH "MYCLI\-REPEAT" "1" "" "myCli 1.0" "myCli Manual"
+.SH NAME
+myCli\-repeat \- Repeats all the words after the command.
+.SH SYNOPSIS
+.B myCli repeat
+[\fB\-\-times\fR \fIvalue\fR] [\fB\-\-loud\fR] \fI<word>...\fR
+.SH DESCRIPTION
+Words are separated by spaces.
+.SH ALIASES
+\fBecho\fR, \fBsay\fR
+.SH OPTIONS
+.TP
+\fB\-\-times\fR \fIvalue\fR
+How many times to repeat (default: 1)
+.TP
+\fB\-\-loud\fR
+Shout the words
+.SH ARGUMENTS
+.TP
+\fIword\fR
+A word to repeat
+.SH EXAMPLES
+.TP
+.B myCli repeat the quick brown fox
+outputs "[the quick brown fox]"
+.SH SEE ALSO
+\fBmyCli\fR(1)
+`
+	if str := sb.String(); str != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+	}
+}
+
+func TestGenMan(t *testing.T) {
+	sb := new(strings.Builder)
+	if err := GenMan(sb, testCommands, ManHeader{Name: "myCli", Description: "A test CLI"}); err != nil {
+		t.Fatalf("the `GenMan` function returned an error: %s", err)
+	}
+
+	expect := `.TH "MYCLI" "1" "" "" ""
+.SH NAME
+myCli \- A test CLI
+.SH SYNOPSIS
+.B myCli
+\fIcommand\fR [\fIargs\fR]
+.SH COMMANDS
+.TP
+.B tell lie
+Makes a preset statement which is factually untrue.
+.TP
+.B repeat
+Repeats all the words after the command.
+.SH SEE ALSO
+\fBmyCli\-tell\-lie\fR(1), \fBmyCli\-repeat\fR(1)
+`
+	if str := sb.String(); str != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+	}
+}
+
+func TestGenManTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenManTree(testCommands, ManHeader{Name: "myCli"}, dir); err != nil {
+		t.Fatalf("the `GenManTree` function returned an error: %s", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	if expect := "myCli-repeat.1 myCli-tell-lie.1 myCli.1"; strings.Join(names, " ") != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, strings.Join(names, " "))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "myCli.1")); err != nil {
+		t.Errorf("the top-level page was not written: %s", err)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if s := escape(".start\n'quote\\back-slash"); s != "\\&.start\n\\&'quote\\eback\\-slash" {
+		t.Errorf("the `escape` function did not escape the text, got %q", s)
+	}
+}
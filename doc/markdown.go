@@ -0,0 +1,169 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/dotvezz/lime"
+)
+
+// GenMarkdown writes a single Markdown reference for the CLI, with a table of contents followed by a section for each
+// command. Sections link to their parent and nested commands through anchors.
+func GenMarkdown(w io.Writer, commands []lime.Command, name string) error {
+	entries := collect(commands, name)
+	link := func(path []string) string {
+		return "#" + slug(strings.Join(path, " "))
+	}
+
+	sb := new(strings.Builder)
+	_, _ = fmt.Fprintf(sb, "# %s\n\n", name)
+	writeContents(sb, entries, link)
+	sb.WriteString("\n")
+	for _, e := range entries {
+		writeMarkdownCommand(sb, e, "##", link)
+	}
+
+	_, err := io.WriteString(w, trimEnd(sb.String()))
+	return err
+}
+
+// GenMarkdownTree writes a Markdown file for each command to dir, named after the keywords leading to it such as
+// `myCli-tell-lie.md`, along with an index file named after the CLI which holds the table of contents. Files link to
+// their parent and nested commands.
+func GenMarkdownTree(commands []lime.Command, name, dir string) error {
+	entries := collect(commands, name)
+	link := func(path []string) string {
+		return pageName(path) + ".md"
+	}
+
+	sb := new(strings.Builder)
+	_, _ = fmt.Fprintf(sb, "# %s\n\n", name)
+	writeContents(sb, entries, link)
+	if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(trimEnd(sb.String())), 0644); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		sb.Reset()
+		writeMarkdownCommand(sb, e, "#", link)
+		if err := os.WriteFile(filepath.Join(dir, link(e.path)), []byte(trimEnd(sb.String())), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collect lists each command in a tree which has a keyword, parents before their nested commands
+func collect(commands []lime.Command, name string) []entry {
+	entries := make([]entry, 0)
+	walk(commands, []string{name}, func(e entry) {
+		entries = append(entries, e)
+	})
+	return entries
+}
+
+// writeContents writes a table of contents as a nested list
+func writeContents(sb *strings.Builder, entries []entry, link func(path []string) string) {
+	for _, e := range entries {
+		indent := strings.Repeat("  ", len(e.path)-2)
+		_, _ = fmt.Fprintf(sb, "%s- [%s](%s)\n", indent, strings.Join(e.path, " "), link(e.path))
+	}
+}
+
+// writeMarkdownCommand writes the section for a single command, with headings at the given level
+func writeMarkdownCommand(sb *strings.Builder, e entry, heading string, link func(path []string) string) {
+	c := e.command
+	_, _ = fmt.Fprintf(sb, "%s %s\n\n", heading, strings.Join(e.path, " "))
+	if len(c.Description) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s\n\n", c.Description)
+	}
+
+	if deprecation := c.Deprecation(); len(deprecation) > 0 {
+		_, _ = fmt.Fprintf(sb, "**Deprecated:** %s\n\n", deprecation)
+	}
+
+	if len(c.Aliases) > 0 {
+		_, _ = fmt.Fprintf(sb, "**Aliases:** `%s`\n\n", strings.Join(c.Aliases, "`, `"))
+	}
+
+	if documented(c) {
+		_, _ = fmt.Fprintf(sb, "%s# Synopsis\n\n```\n%s\n```\n\n", heading, strings.Join(e.path[:len(e.path)-1], " ")+" "+c.Synopsis(nil))
+	}
+
+	if len(c.Help) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s\n\n", c.Help)
+	}
+
+	if len(c.Flags) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s# Flags\n\n| Flag | Description |\n| --- | --- |\n", heading)
+		for _, f := range c.Flags {
+			flag := "--" + f.Name
+			if !f.Bool {
+				flag += " <value>"
+			}
+			_, _ = fmt.Fprintf(sb, "| `%s` | %s |\n", flag, cell(f.Describe()))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(c.Arguments) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s# Arguments\n\n| Argument | Description |\n| --- | --- |\n", heading)
+		for _, a := range c.Arguments {
+			_, _ = fmt.Fprintf(sb, "| `%s` | %s |\n", a.Name, cell(a.Describe()))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(c.Usage) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s# Examples\n\n", heading)
+		for _, u := range c.Usage {
+			_, _ = fmt.Fprintf(sb, "```\n%s\n```\n\n%s\n\n", u.Example, u.Explanation)
+		}
+	}
+
+	related := make([]string, 0)
+	if len(e.path) > 2 {
+		parent := e.path[:len(e.path)-1]
+		related = append(related, fmt.Sprintf("- Parent: [%s](%s)", strings.Join(parent, " "), link(parent)))
+	}
+	for i := range c.Commands {
+		keyword := strings.Trim(c.Commands[i].Keyword, " ")
+		if len(keyword) == 0 || c.Commands[i].Hidden {
+			continue
+		}
+		child := append(append([]string{}, e.path...), keyword)
+		related = append(related, fmt.Sprintf("- [%s](%s)", strings.Join(child, " "), link(child)))
+	}
+	if len(related) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s# See also\n\n%s\n\n", heading, strings.Join(related, "\n"))
+	}
+}
+
+// slug returns the anchor which Markdown renderers such as GitHub's generate for a heading
+func slug(heading string) string {
+	sb := new(strings.Builder)
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// cell escapes text for a Markdown table cell
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
+
+// trimEnd leaves a document with a single trailing newline
+func trimEnd(s string) string {
+	return strings.TrimRight(s, "\n") + "\n"
+}
@@ -0,0 +1,114 @@
+package doc
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGenMarkdown(t *testing.T) {
+	sb := new(strings.Builder)
+	if err := GenMarkdown(sb, testCommands, "myCli"); err != nil {
+		t.Fatalf("the `GenMarkdown` function returned an error: %s", err)
+	}
+
+	expect := "# myCli\n" +
+		"\n" +
+		"- [myCli tell](#mycli-tell)\n" +
+		"  - [myCli tell lie](#mycli-tell-lie)\n" +
+		"- [myCli repeat](#mycli-repeat)\n" +
+		"\n" +
+		"## myCli tell\n" +
+		"\n" +
+		"Makes statements\n" +
+		"\n" +
+		"### See also\n" +
+		"\n" +
+		"- [myCli tell lie](#mycli-tell-lie)\n" +
+		"\n" +
+		"## myCli tell lie\n" +
+		"\n" +
+		"Makes a preset statement which is factually untrue.\n" +
+		"\n" +
+		"### Synopsis\n" +
+		"\n" +
+		"```\n" +
+		"myCli tell lie\n" +
+		"```\n" +
+		"\n" +
+		"### See also\n" +
+		"\n" +
+		"- Parent: [myCli tell](#mycli-tell)\n" +
+		"\n" +
+		"## myCli repeat\n" +
+		"\n" +
+		"Repeats all the words after the command.\n" +
+		"\n" +
+		"**Aliases:** `echo`, `say`\n" +
+		"\n" +
+		"### Synopsis\n" +
+		"\n" +
+		"```\n" +
+		"myCli repeat [flags] <word>...\n" +
+		"```\n" +
+		"\n" +
+		"Words are separated by spaces.\n" +
+		"\n" +
+		"### Flags\n" +
+		"\n" +
+		"| Flag | Description |\n" +
+		"| --- | --- |\n" +
+		"| `--times <value>` | How many times to repeat (default: 1) |\n" +
+		"| `--loud` | Shout the words |\n" +
+		"\n" +
+		"### Arguments\n" +
+		"\n" +
+		"| Argument | Description |\n" +
+		"| --- | --- |\n" +
+		"| `word` | A word to repeat |\n" +
+		"\n" +
+		"### Examples\n" +
+		"\n" +
+		"```\n" +
+		"myCli repeat the quick brown fox\n" +
+		"```\n" +
+		"\n" +
+		"outputs \"[the quick brown fox]\"\n"
+	if str := sb.String(); str != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+	}
+}
+
+func TestGenMarkdownTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenMarkdownTree(testCommands, "myCli", dir); err != nil {
+		t.Fatalf("the `GenMarkdownTree` function returned an error: %s", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	if expect := "myCli-repeat.md myCli-tell-lie.md myCli-tell.md myCli.md"; strings.Join(names, " ") != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, strings.Join(names, " "))
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "myCli-tell-lie.md"))
+	if err != nil {
+		t.Fatalf("the page for `tell lie` was not written: %s", err)
+	}
+	if !strings.HasPrefix(string(b), "# myCli tell lie\n") || !strings.Contains(string(b), "[myCli tell](myCli-tell.md)") {
+		t.Errorf("the page for `tell lie` does not link to its parent page, got:\n%s", b)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	if s := slug("myCli user <id> delete_all"); s != "mycli-user-id-delete_all" {
+		t.Errorf("the `slug` function returned %q", s)
+	}
+}
@@ -55,6 +55,13 @@ var commands = []lime.Command{
 	{
 		Keyword:     "repeat",
 		Description: "Repeats all the words after the command.",
+		Arguments: []lime.Argument{
+			{
+				Name:        "word",
+				Description: "A word to repeat",
+				Variadic:    true,
+			},
+		},
 		Usage: []lime.Usage{
 			{
 				Example:     "mycli repeat the quick brown fox",
@@ -62,7 +69,7 @@ var commands = []lime.Command{
 			},
 			{
 				Example:     "mycli repeat",
-				Explanation: `returns an error: "there are no words to repeat"`,
+				Explanation: `returns a usage error, because at least one word is required`,
 			},
 		},
 		Func: func(args []string, _ io.Writer) error {
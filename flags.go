@@ -0,0 +1,45 @@
+package lime
+
+import "fmt"
+
+// Flag defines a named option accepted by a Command, given as `--name value` or `--name=value`.
+// A single leading dash is also accepted.
+type Flag struct {
+	// The name of the flag, without leading dashes
+	Name string
+	// A brief description of the flag, used in command-specific --help output
+	Description string
+	// The value of the flag when it is not given
+	Default string
+	// Whether the flag must be given
+	Required bool
+	// Whether the flag is a switch which takes no value. A Bool flag given as `--name` has the value "true"
+	Bool bool
+	// Whether the flag is also accepted by the nested commands of the command which declares it
+	Persistent bool
+}
+
+// Describe returns the description of the flag, along with its default value if it has one
+func (f Flag) Describe() string {
+	if len(f.Default) > 0 {
+		return fmt.Sprintf("%s (default: %s)", f.Description, f.Default)
+	}
+	return f.Description
+}
+
+// Flags holds the values of the flags given to a Command, keyed by the name of the flag. Flags which were not given
+// hold their Default value.
+type Flags map[string]string
+
+// FlagError describes a problem with a single flag
+type FlagError struct {
+	// The name of the flag
+	Name string
+	// What is wrong with the flag
+	Reason string
+}
+
+// Error implements the error interface
+func (e *FlagError) Error() string {
+	return fmt.Sprintf("flag --%s: %s", e.Name, e.Reason)
+}
@@ -1,21 +1,81 @@
 package lime
 
-import "io"
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
 
 // Command defines the structure of a cli command.
 type Command struct {
-	// The keyword which invokes this command
+	// The keyword which invokes this command. A keyword wrapped in angle brackets, such as `<id>`, is a placeholder
+	// which matches any single argument and captures it as a Param under the name between the brackets
 	Keyword string
+	// Other keywords which also invoke this command, such as "rm" for a "remove" command. Placeholders are not
+	// supported as aliases
+	Aliases []string
 	// A brief description of the command, used in all --help output
 	Description string
 	// A collection of examples and explanations for the command, used in command-specific --usage output
 	Usage []Usage
 	// A helpful bit of information about the command, used in all --help output
 	Help string
+	// The group the command is listed under in the help for the whole CLI, such as "Admin commands". Nested commands
+	// are listed under the group of their parent unless they have their own
+	Group string
+	// Whether the command is left out of help and generated documentation, while remaining runnable. Its nested
+	// commands are left out as well
+	Hidden bool
+	// Why the command is deprecated. A deprecated command prints a warning to the error stream when it is invoked
+	Deprecated string
+	// The keyword of the command which replaces a deprecated command, mentioned in its warning
+	ReplacedBy string
+	// The positional arguments accepted by this command, used to validate args and in command-specific --help output
+	Arguments []Argument
+	// The flags accepted by this command, used to parse args and in command-specific --help output
+	Flags []Flag
+	// A function to run before the command's function, after its args are validated. If it returns an error, the
+	// command's function is skipped
+	PreRun Handler
+	// A function to run after the command's function, which runs even if PreRun or the command's function returned an
+	// error
+	PostRun Handler
+	// Middleware wrapping the invocation of this command and any nested commands. Middleware registered on a parent
+	// command runs before middleware registered on its nested commands
+	Middleware []Middleware
 	// Nested commands
 	Commands []Command
 	// The function to run when this command is invoked
 	Func Func
+	// The function to run when this command is invoked, if it needs the context of the invocation. Takes precedence
+	// over Func when both are set
+	ContextFunc ContextFunc
+}
+
+// Deprecation describes why the command is deprecated and what replaces it. Returns an empty string if the command is
+// not deprecated.
+func (c Command) Deprecation() string {
+	if len(c.Deprecated) == 0 {
+		return ""
+	}
+	if len(c.ReplacedBy) > 0 {
+		return fmt.Sprintf("%s, use %q instead", c.Deprecated, c.ReplacedBy)
+	}
+	return c.Deprecated
+}
+
+// Synopsis returns a one-line summary of how to invoke the command, given the flags it inherits from its parents, such
+// as `repeat [flags] <word>...`
+func (c Command) Synopsis(inherited []Flag) string {
+	parts := []string{c.Keyword}
+	if len(c.Flags) > 0 || len(inherited) > 0 {
+		parts = append(parts, "[flags]")
+	}
+	for _, a := range c.Arguments {
+		parts = append(parts, a.Synopsis())
+	}
+	return strings.Join(parts, " ")
 }
 
 // Usage defines the structure of a Usage entry
@@ -29,5 +89,12 @@ type Usage struct {
 // Func is the signature of a function to run when a Command is invoked.
 type Func func(args []string, out io.Writer) error
 
+// ContextFunc is the signature of a function to run when a Command is invoked, which also receives a context.Context
+// carrying the values captured while matching the Command, such as its Params.
+type ContextFunc func(ctx context.Context, args []string, out io.Writer) error
+
+// Params holds the values captured by placeholder keywords, keyed by the name of the placeholder
+type Params map[string]string
+
 // Option is a bit mask value for setting options on a CLI
 type Option int64
@@ -0,0 +1,27 @@
+package lime
+
+import (
+	"context"
+	"io"
+)
+
+// Invocation describes a single invocation of a Command
+type Invocation struct {
+	// The args which matched the Command, including the values of any placeholders
+	Path []string
+	// The args given to the Command, after its Path
+	Args []string
+	// The input stream of the CLI
+	In io.Reader
+	// The output stream given to the Command's Func
+	Out io.Writer
+	// The error output stream of the CLI
+	Err io.Writer
+}
+
+// Handler runs an Invocation
+type Handler func(ctx context.Context, inv Invocation) error
+
+// Middleware wraps a Handler with behavior of its own. It may call next, possibly with a changed ctx or Invocation,
+// or return without calling it to short-circuit the Invocation.
+type Middleware func(next Handler) Handler
@@ -11,6 +11,33 @@ const (
 	NoInteractiveMode lime.Option = 1 << iota
 	// PrintErrors enables output of errors to stdout
 	PrintErrors
+	// RecoverPanics enables recovery from panics in commands, writing a crash report to a temporary file
+	RecoverPanics
+	// BuiltinHelp enables the `help [command]` builtin of the interactive mode, as well as the `help` subcommand
+	BuiltinHelp
+	// BuiltinHistory enables the `history` builtin of the interactive mode
+	BuiltinHistory
+	// BuiltinClear enables the `clear` builtin of the interactive mode
+	BuiltinClear
+	// BuiltinSource enables the `source <file>` builtin of the interactive mode
+	BuiltinSource
+	// BuiltinAlias enables the `alias [name[=value]]` builtin of the interactive mode
+	BuiltinAlias
+	// BuiltinSet enables the `set [name[=value]]` builtin of the interactive mode, for the variables of the session
+	BuiltinSet
+	// BuiltinPrompt enables the `prompt [template]` builtin of the interactive mode, see `cli.CLI.SetPromptTemplate`
+	BuiltinPrompt
+	// RunControl enables sourcing an rc file when the interactive mode starts, see `cli.CLI.Run`
+	RunControl
+	// StopOnError stops the batch mode, used when the input is not a terminal, at the first error
+	StopOnError
+	// NoBanner suppresses the banner shown when the interactive mode starts
+	NoBanner
+	// BannerTips adds a tip, drawn from the usage examples of the commands, to the default banner
+	BannerTips
+	// SpecCommand enables the hidden `__commands` command, which writes the command tree as JSON, see
+	// `cli.CLI.WriteSpec`
+	SpecCommand
 )
 
 // IsValid returns true if the option passed is a power of 2, or returns false otherwise
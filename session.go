@@ -0,0 +1,160 @@
+package lime
+
+import (
+	"io"
+	"sync"
+)
+
+// Session holds the state shared by the commands run in one interactive session, or by a single command run from args.
+// It is safe for concurrent use.
+type Session struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	// The keys of values, in the order they were set
+	keys    []string
+	vars    map[string]string
+	history []string
+	lastErr error
+}
+
+// NewSession creates an empty Session
+func NewSession() *Session {
+	return &Session{
+		values:  make(map[string]interface{}),
+		keys:    make([]string, 0),
+		vars:    make(map[string]string),
+		history: make([]string, 0),
+	}
+}
+
+// Set stores a value in the Session under the given key. Replacing a value moves its key to the end of the order in
+// which values are closed.
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeKey(key)
+	s.values[key] = value
+	s.keys = append(s.keys, key)
+}
+
+// Get returns the value stored in the Session under the given key. Returns false if there is no such value.
+func (s *Session) Get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Delete removes the value stored in the Session under the given key
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeKey(key)
+	delete(s.values, key)
+}
+
+// removeKey removes a key from the order of the Session's values. The caller must hold the lock.
+func (s *Session) removeKey(key string) {
+	for i := range s.keys {
+		if s.keys[i] == key {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Value returns the value stored in the Session under the given key as a T.
+// Returns false if there is no such value, or if it is not a T.
+func Value[T any](s *Session, key string) (T, bool) {
+	value, ok := s.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := value.(T)
+	return t, ok
+}
+
+// SetVar sets a variable of the Session
+func (s *Session) SetVar(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vars[name] = value
+}
+
+// Var returns a variable of the Session. Returns false if the variable is not set.
+func (s *Session) Var(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.vars[name]
+	return value, ok
+}
+
+// DeleteVar unsets a variable of the Session
+func (s *Session) DeleteVar(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.vars, name)
+}
+
+// Vars returns a copy of all variables of the Session
+func (s *Session) Vars() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vars := make(map[string]string, len(s.vars))
+	for name, value := range s.vars {
+		vars[name] = value
+	}
+	return vars
+}
+
+// AddHistory appends a line of input to the history of the Session
+func (s *Session) AddHistory(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, line)
+}
+
+// History returns a copy of the lines of input given in the Session, oldest first
+func (s *Session) History() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.history...)
+}
+
+// SetLastErr records the error returned by the most recent command of the Session, or nil if it succeeded
+func (s *Session) SetLastErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+}
+
+// LastErr returns the error returned by the most recent command of the Session, or nil if it succeeded
+func (s *Session) LastErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// Close tears down the Session, closing any stored values which implement io.Closer in the reverse of the order they
+// were set, and clearing all of its state. Returns the first error returned by a Close.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if closer, ok := s.values[s.keys[i]].(io.Closer); ok {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+
+	s.values = make(map[string]interface{})
+	s.keys = make([]string, 0)
+	s.vars = make(map[string]string)
+	s.history = make([]string, 0)
+	s.lastErr = nil
+	return err
+}
@@ -0,0 +1,100 @@
+package lime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type closer struct {
+	closed bool
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return errors.New("closed")
+}
+
+// orderedCloser records its name in a shared slice when it is closed
+type orderedCloser struct {
+	name   string
+	closed *[]string
+}
+
+func (c orderedCloser) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func TestSession(t *testing.T) {
+	s := NewSession()
+
+	// Ensure values can be stored and read back with their types
+	{
+		s.Set("project", "lime")
+		s.Set("count", 3)
+
+		if project, ok := Value[string](s, "project"); !ok || project != "lime" {
+			t.Errorf("the `Value` function did not return the stored string, got %q", project)
+		}
+
+		if _, ok := Value[string](s, "count"); ok {
+			t.Error("the `Value` function returned a value of the wrong type")
+		}
+
+		s.Delete("count")
+		if _, ok := s.Get("count"); ok {
+			t.Error("the `Delete` method did not remove the value")
+		}
+	}
+
+	// Ensure variables and history are kept
+	{
+		s.SetVar("env", "prod")
+		if env, ok := s.Var("env"); !ok || env != "prod" {
+			t.Errorf("the `Var` method did not return the variable, got %q", env)
+		}
+
+		s.AddHistory("login")
+		s.AddHistory("deploy")
+		if h := s.History(); len(h) != 2 || h[1] != "deploy" {
+			t.Errorf("the `History` method did not return the history, got %v", h)
+		}
+	}
+
+	// Ensure closing the session closes its values and clears its state
+	{
+		c := &closer{}
+		s.Set("db", c)
+		s.SetLastErr(errors.New("failed"))
+
+		if err := s.Close(); err == nil || err.Error() != "closed" {
+			t.Errorf("the `Close` method did not return the error from closing a value, got %v", err)
+		}
+
+		if !c.closed {
+			t.Error("the `Close` method did not close the stored value")
+		}
+
+		if _, ok := s.Get("project"); ok || len(s.Vars()) > 0 || len(s.History()) > 0 || s.LastErr() != nil {
+			t.Error("the `Close` method did not clear the session")
+		}
+	}
+
+	// Ensure values are closed in the reverse of the order they were set
+	{
+		var closed []string
+		s.Set("conn", orderedCloser{"conn", &closed})
+		s.Set("tx", orderedCloser{"tx", &closed})
+		s.Set("cache", orderedCloser{"cache", &closed})
+		s.Set("log", orderedCloser{"log", &closed})
+		s.Delete("cache")
+		s.Set("conn", orderedCloser{"conn2", &closed})
+
+		_ = s.Close()
+
+		if got := strings.Join(closed, " "); got != "conn2 log tx" {
+			t.Errorf("the `Close` method closed the values in the wrong order, got %q", got)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// placeholderKey stands in for the keyword of any placeholder command when pairing commands, since renaming a
+// placeholder such as `<id>` to `<userId>` does not change how a CLI is invoked
+const placeholderKey = "<>"
+
+// Change is a difference between two versions of a command tree
+type Change struct {
+	// The keywords leading to the changed command, including its own
+	Path []string `json:"path"`
+	// What changed, such as "flag --env removed"
+	Description string `json:"description"`
+	// Whether the change can break existing invocations of the CLI
+	Breaking bool `json:"breaking"`
+}
+
+// String renders the change on a single line, marking breaking changes
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("%s: %s: %s", kind, strings.Join(c.Path, " "), c.Description)
+}
+
+// Diff compares two versions of a CLI's command tree, listing removed, renamed and added commands and aliases, as well
+// as changes to their flags and arguments. A command whose keyword was kept as an alias of another command counts as
+// renamed, which is not breaking, unless the other command also existed before. Each command's flags are compared
+// along with the persistent flags it inherits.
+func Diff(before, after Spec) []Change {
+	d := &differ{changes: make([]Change, 0)}
+	d.commands([]string{after.Name}, before.Commands, after.Commands, nil, nil)
+	return d.changes
+}
+
+// HasBreaking returns true if any of the changes is breaking
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// differ collects the changes between two command trees
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(path []string, breaking bool, format string, a ...interface{}) {
+	d.changes = append(d.changes, Change{Path: path, Description: fmt.Sprintf(format, a...), Breaking: breaking})
+}
+
+// commands compares two lists of sibling commands, given the flags each version inherits from their parents. Commands
+// are paired by keyword first, then by alias. A command whose keyword became an alias of a command which is already
+// paired counts as removed, since its keyword now runs a different command.
+func (d *differ) commands(path []string, before, after []Command, beforeInherited, afterInherited []Flag) {
+	matched := make(map[int]bool)
+	unmatched := func(predicate func(Command) bool) int {
+		for i := range after {
+			if !matched[i] && predicate(after[i]) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	pairs := make([]int, len(before))
+	for j, o := range before {
+		pairs[j] = unmatched(func(n Command) bool {
+			return key(n.Keyword) == key(o.Keyword)
+		})
+		if pairs[j] >= 0 {
+			matched[pairs[j]] = true
+		}
+	}
+	renamed := make(map[int]bool)
+	for j, o := range before {
+		if pairs[j] >= 0 {
+			continue
+		}
+		pairs[j] = unmatched(func(n Command) bool {
+			return contains(n.Aliases, o.Keyword)
+		})
+		if pairs[j] >= 0 {
+			matched[pairs[j]] = true
+			renamed[j] = true
+		}
+	}
+
+	for j, o := range before {
+		i := pairs[j]
+		if i < 0 {
+			if i = find(after, func(n Command) bool { return contains(n.Aliases, o.Keyword) }); i >= 0 {
+				d.add(join(path, o.Keyword), true, "command removed, %q is now an alias of %q", o.Keyword, after[i].Keyword)
+			} else {
+				d.add(join(path, o.Keyword), true, "command removed")
+			}
+			continue
+		}
+		if renamed[j] {
+			d.add(join(path, o.Keyword), false, "renamed to %q, which keeps %q as an alias", after[i].Keyword, o.Keyword)
+		}
+		d.command(join(path, after[i].Keyword), o, after[i], beforeInherited, afterInherited)
+	}
+
+	for i, n := range after {
+		if !matched[i] {
+			d.add(join(path, n.Keyword), false, "command added")
+		}
+	}
+}
+
+// command compares two versions of a single command, given the flags each version inherits from its parents
+func (d *differ) command(path []string, before, after Command, beforeInherited, afterInherited []Flag) {
+	for _, alias := range before.Aliases {
+		if !contains(after.Aliases, alias) && alias != after.Keyword {
+			d.add(path, true, "alias %q removed", alias)
+		}
+	}
+	for _, alias := range after.Aliases {
+		if !contains(before.Aliases, alias) && alias != before.Keyword {
+			d.add(path, false, "alias %q added", alias)
+		}
+	}
+
+	if before.Runnable && !after.Runnable {
+		d.add(path, true, "no longer runnable")
+	}
+	if !before.Hidden && after.Hidden {
+		d.add(path, false, "hidden")
+	}
+	if len(before.Deprecated) == 0 && len(after.Deprecated) > 0 {
+		d.add(path, false, "deprecated: %s", after.Deprecated)
+	}
+
+	d.flags(path, resolve(before.Flags, beforeInherited), resolve(after.Flags, afterInherited))
+	d.arguments(path, before.Arguments, after.Arguments)
+	d.commands(path, before.Commands, after.Commands, inherit(beforeInherited, before.Flags), inherit(afterInherited, after.Flags))
+}
+
+// flags compares two versions of the flags of a command
+func (d *differ) flags(path []string, before, after []Flag) {
+	for _, o := range before {
+		i := find(after, func(n Flag) bool {
+			return n.Name == o.Name
+		})
+		if i < 0 {
+			d.add(path, true, "flag --%s removed", o.Name)
+			continue
+		}
+
+		n := after[i]
+		if o.Default != n.Default {
+			d.add(path, true, "default of flag --%s changed from %q to %q", o.Name, o.Default, n.Default)
+		}
+		if !o.Required && n.Required {
+			d.add(path, true, "flag --%s is now required", o.Name)
+		}
+		if o.Bool != n.Bool {
+			d.add(path, true, "flag --%s changed between a switch and a flag with a value", o.Name)
+		}
+		if o.Persistent && !n.Persistent {
+			d.add(path, true, "flag --%s is no longer persistent", o.Name)
+		}
+	}
+
+	for _, n := range after {
+		if find(before, func(o Flag) bool { return o.Name == n.Name }) >= 0 {
+			continue
+		}
+		if n.Required {
+			d.add(path, true, "required flag --%s added", n.Name)
+		} else {
+			d.add(path, false, "flag --%s added", n.Name)
+		}
+	}
+}
+
+// arguments compares two versions of the positional arguments of a command, by position
+func (d *differ) arguments(path []string, before, after []Argument) {
+	for i, n := range after {
+		if i >= len(before) {
+			if len(before) > 0 && before[len(before)-1].Variadic {
+				d.add(path, true, "argument %s added after a variadic argument", n.Name)
+			} else if n.Optional {
+				d.add(path, false, "optional argument %s added", n.Name)
+			} else {
+				d.add(path, true, "required argument %s added", n.Name)
+			}
+			continue
+		}
+
+		o := before[i]
+		if o.Optional && !n.Optional {
+			d.add(path, true, "argument %s is now required", n.Name)
+		}
+		if o.Variadic && !n.Variadic {
+			d.add(path, true, "argument %s is no longer variadic", n.Name)
+		}
+		if o.Type != n.Type && n.Type != "string" {
+			d.add(path, true, "type of argument %s changed from %s to %s", n.Name, o.Type, n.Type)
+		}
+		if o.Type == "enum" && n.Type == "enum" {
+			for _, value := range o.Enum {
+				if !contains(n.Enum, value) {
+					d.add(path, true, "value %q of argument %s removed", value, n.Name)
+				}
+			}
+		}
+	}
+
+	if len(before) > len(after) && (len(after) == 0 || !after[len(after)-1].Variadic) {
+		for _, o := range before[len(after):] {
+			d.add(path, true, "argument %s removed", o.Name)
+		}
+	}
+}
+
+// resolve returns the flags accepted by a command, its own along with those it inherits from its parents. Inherited
+// flags are not persistent from the command's point of view, so that moving a flag to a parent as a persistent flag is
+// not a change for the command itself.
+func resolve(own, inherited []Flag) []Flag {
+	flags := append([]Flag{}, own...)
+	for i := len(inherited) - 1; i >= 0; i-- {
+		f := inherited[i]
+		if find(flags, func(o Flag) bool { return o.Name == f.Name }) < 0 {
+			f.Persistent = false
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// inherit returns the flags passed on to the nested commands of a command, given the flags it inherits itself
+func inherit(inherited, own []Flag) []Flag {
+	flags := append([]Flag{}, inherited...)
+	for _, f := range own {
+		if f.Persistent {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// key returns the keyword used to pair a command with its other version
+func key(keyword string) string {
+	if len(keyword) > 2 && strings.HasPrefix(keyword, "<") && strings.HasSuffix(keyword, ">") {
+		return placeholderKey
+	}
+	return keyword
+}
+
+// join returns a copy of the path with the keyword appended
+func join(path []string, keyword string) []string {
+	return append(append([]string{}, path...), keyword)
+}
+
+// find returns the index of the first item which satisfies the predicate, or -1 if there is none
+func find[T any](items []T, predicate func(T) bool) int {
+	for i := range items {
+		if predicate(items[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// contains returns true if the value is in the list
+func contains(list []string, value string) bool {
+	for _, s := range list {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
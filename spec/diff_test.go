@@ -0,0 +1,159 @@
+package spec
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := Spec{Name: "myCli", Commands: []Command{
+		{
+			Keyword:  "deploy",
+			Runnable: true,
+			Flags: []Flag{
+				{Name: "env", Default: "staging"},
+				{Name: "force", Bool: true},
+			},
+			Arguments: []Argument{
+				{Name: "target", Type: "string"},
+				{Name: "region", Type: "enum", Enum: []string{"us", "eu"}, Optional: true},
+			},
+		},
+		{Keyword: "rm", Runnable: true},
+		{Keyword: "status", Runnable: true},
+		{
+			Keyword: "user",
+			Commands: []Command{
+				{Keyword: "<id>", Runnable: true, Aliases: []string{"show"}},
+			},
+		},
+	}}
+	after := Spec{Name: "myCli", Commands: []Command{
+		{
+			Keyword:  "deploy",
+			Runnable: true,
+			Flags: []Flag{
+				{Name: "env", Default: "production"},
+				{Name: "dry-run", Bool: true},
+			},
+			Arguments: []Argument{
+				{Name: "target", Type: "string"},
+				{Name: "region", Type: "enum", Enum: []string{"us"}},
+				{Name: "note", Type: "string", Optional: true},
+			},
+		},
+		{Keyword: "delete", Runnable: true, Aliases: []string{"rm"}},
+		{Keyword: "version", Runnable: true},
+		{
+			Keyword: "user",
+			Commands: []Command{
+				{Keyword: "<userId>", Runnable: true, Deprecated: "use get"},
+			},
+		},
+	}}
+
+	expect := []string{
+		"breaking: myCli deploy: default of flag --env changed from \"staging\" to \"production\"",
+		"breaking: myCli deploy: flag --force removed",
+		"non-breaking: myCli deploy: flag --dry-run added",
+		"breaking: myCli deploy: argument region is now required",
+		"breaking: myCli deploy: value \"eu\" of argument region removed",
+		"non-breaking: myCli deploy: optional argument note added",
+		"non-breaking: myCli rm: renamed to \"delete\", which keeps \"rm\" as an alias",
+		"breaking: myCli status: command removed",
+		"breaking: myCli user <userId>: alias \"show\" removed",
+		"non-breaking: myCli user <userId>: deprecated: use get",
+		"non-breaking: myCli version: command added",
+	}
+
+	changes := Diff(before, after)
+	if len(changes) != len(expect) {
+		t.Errorf("the `Diff` function found %d changes instead of %d", len(changes), len(expect))
+	}
+	for i := 0; i < len(changes) && i < len(expect); i++ {
+		if s := changes[i].String(); s != expect[i] {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect[i], s)
+		}
+	}
+
+	if !HasBreaking(changes) {
+		t.Error("the `HasBreaking` function did not find the breaking changes")
+	}
+
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("the `Diff` function found changes between identical trees: %v", changes)
+	}
+}
+
+func TestDiff_AliasTakeover(t *testing.T) {
+	before := Spec{Name: "myCli", Commands: []Command{
+		{Keyword: "rm", Runnable: true},
+		{Keyword: "remove", Runnable: true},
+	}}
+	after := Spec{Name: "myCli", Commands: []Command{
+		{Keyword: "remove", Runnable: true, Aliases: []string{"rm"}},
+	}}
+
+	// Ensure a command whose keyword became an alias of another command counts as removed, whatever the order
+	expect := []string{
+		"breaking: myCli rm: command removed, \"rm\" is now an alias of \"remove\"",
+		"non-breaking: myCli remove: alias \"rm\" added",
+	}
+
+	changes := Diff(before, after)
+	if len(changes) != len(expect) {
+		t.Errorf("the `Diff` function found %d changes instead of %d", len(changes), len(expect))
+	}
+	for i := 0; i < len(changes) && i < len(expect); i++ {
+		if s := changes[i].String(); s != expect[i] {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect[i], s)
+		}
+	}
+}
+
+func TestDiff_InheritedFlags(t *testing.T) {
+	own := Spec{Name: "myCli", Commands: []Command{
+		{
+			Keyword:  "db",
+			Commands: []Command{{Keyword: "migrate", Runnable: true, Flags: []Flag{{Name: "host"}}}},
+		},
+	}}
+	persistent := Spec{Name: "myCli", Commands: []Command{
+		{
+			Keyword:  "db",
+			Flags:    []Flag{{Name: "host", Persistent: true}},
+			Commands: []Command{{Keyword: "migrate", Runnable: true}},
+		},
+	}}
+	local := Spec{Name: "myCli", Commands: []Command{
+		{
+			Keyword:  "db",
+			Flags:    []Flag{{Name: "host"}},
+			Commands: []Command{{Keyword: "migrate", Runnable: true}},
+		},
+	}}
+
+	// Ensure moving a flag to a parent as a persistent flag does not remove it from the nested command
+	{
+		changes := Diff(own, persistent)
+		if expect := "non-breaking: myCli db: flag --host added"; len(changes) != 1 || changes[0].String() != expect {
+			t.Errorf("\nExpected: \n%s\nBut Got:\n%v\n", expect, changes)
+		}
+	}
+
+	// Ensure a persistent flag which is no longer persistent is removed from the nested commands
+	{
+		expect := []string{
+			"breaking: myCli db: flag --host is no longer persistent",
+			"breaking: myCli db migrate: flag --host removed",
+		}
+		changes := Diff(persistent, local)
+		if len(changes) != len(expect) {
+			t.Errorf("the `Diff` function found %d changes instead of %d", len(changes), len(expect))
+		}
+		for i := 0; i < len(changes) && i < len(expect); i++ {
+			if s := changes[i].String(); s != expect[i] {
+				t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect[i], s)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package spec describes a tree of `lime.Command`s in a form which can be serialized, such as JSON, so that other tools
+// can introspect a CLI without parsing its help.
+package spec
+
+import (
+	"strings"
+
+	"github.com/dotvezz/lime"
+)
+
+// Spec describes a CLI and its commands
+type Spec struct {
+	// The name of the CLI
+	Name string `json:"name"`
+	// The version of the CLI
+	Version string `json:"version,omitempty"`
+	// The top-level commands of the CLI
+	Commands []Command `json:"commands"`
+}
+
+// Command describes a `lime.Command` and its nested commands
+type Command struct {
+	Keyword     string     `json:"keyword"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Help        string     `json:"help,omitempty"`
+	Group       string     `json:"group,omitempty"`
+	Usage       []Usage    `json:"usage,omitempty"`
+	Arguments   []Argument `json:"arguments,omitempty"`
+	Flags       []Flag     `json:"flags,omitempty"`
+	// Whether the command has a function to run, rather than only nested commands
+	Runnable   bool      `json:"runnable"`
+	Hidden     bool      `json:"hidden,omitempty"`
+	Deprecated string    `json:"deprecated,omitempty"`
+	ReplacedBy string    `json:"replacedBy,omitempty"`
+	Commands   []Command `json:"commands,omitempty"`
+}
+
+// Usage describes a `lime.Usage`
+type Usage struct {
+	Example     string `json:"example"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// Argument describes a `lime.Argument`
+type Argument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+	Variadic    bool   `json:"variadic,omitempty"`
+	// The name of the type of the argument, such as "int"
+	Type string   `json:"type"`
+	Enum []string `json:"enum,omitempty"`
+}
+
+// Flag describes a `lime.Flag`
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Bool        bool   `json:"bool,omitempty"`
+	Persistent  bool   `json:"persistent,omitempty"`
+}
+
+// New describes a CLI with the given name, version and commands. Hidden commands are included, and marked as hidden.
+// Commands with no keyword can not be invoked, so they are left out.
+func New(name, version string, commands []lime.Command) Spec {
+	return Spec{Name: name, Version: version, Commands: describe(commands)}
+}
+
+// describe describes each command in a tree which has a keyword
+func describe(commands []lime.Command) []Command {
+	described := make([]Command, 0, len(commands))
+	for _, c := range commands {
+		keyword := strings.Trim(c.Keyword, " ")
+		if len(keyword) == 0 {
+			continue
+		}
+
+		d := Command{
+			Keyword:     keyword,
+			Aliases:     c.Aliases,
+			Description: c.Description,
+			Help:        c.Help,
+			Group:       c.Group,
+			Runnable:    c.Func != nil || c.ContextFunc != nil,
+			Hidden:      c.Hidden,
+			Deprecated:  c.Deprecated,
+			ReplacedBy:  c.ReplacedBy,
+		}
+		for _, u := range c.Usage {
+			d.Usage = append(d.Usage, Usage{Example: u.Example, Explanation: u.Explanation})
+		}
+		for _, a := range c.Arguments {
+			d.Arguments = append(d.Arguments, Argument{
+				Name:        a.Name,
+				Description: a.Description,
+				Optional:    a.Optional,
+				Variadic:    a.Variadic,
+				Type:        a.Type.String(),
+				Enum:        a.Enum,
+			})
+		}
+		for _, f := range c.Flags {
+			d.Flags = append(d.Flags, Flag{
+				Name:        f.Name,
+				Description: f.Description,
+				Default:     f.Default,
+				Required:    f.Required,
+				Bool:        f.Bool,
+				Persistent:  f.Persistent,
+			})
+		}
+		if len(c.Commands) > 0 {
+			d.Commands = describe(c.Commands)
+		}
+
+		described = append(described, d)
+	}
+	return described
+}
@@ -0,0 +1,50 @@
+package spec
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/dotvezz/lime"
+)
+
+var noop = func(_ []string, _ io.Writer) error {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	s := New("myCli", "1.0", []lime.Command{
+		{
+			Keyword:     "user",
+			Description: "Manages users",
+			Flags:       []lime.Flag{{Name: "host", Default: "localhost", Persistent: true}},
+			Commands: []lime.Command{
+				{
+					Keyword:    "remove",
+					Aliases:    []string{"rm"},
+					Arguments:  []lime.Argument{{Name: "id", Type: lime.IntArgument}},
+					Usage:      []lime.Usage{{Example: "myCli user remove 1"}},
+					Deprecated: "it was renamed",
+					ReplacedBy: "delete",
+					Func:       noop,
+				},
+				{Keyword: "debug", Hidden: true, Func: noop},
+			},
+		},
+		{Description: "no keyword"},
+	})
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("the Spec could not be marshaled: %s", err)
+	}
+
+	expect := `{"name":"myCli","version":"1.0","commands":[{"keyword":"user","description":"Manages users",` +
+		`"flags":[{"name":"host","default":"localhost","persistent":true}],"runnable":false,"commands":[` +
+		`{"keyword":"remove","aliases":["rm"],"usage":[{"example":"myCli user remove 1"}],` +
+		`"arguments":[{"name":"id","type":"int"}],"runnable":true,"deprecated":"it was renamed","replacedBy":"delete"},` +
+		`{"keyword":"debug","runnable":true,"hidden":true}]}]}`
+	if str := string(b); str != expect {
+		t.Errorf("\nExpected: \n%s\nBut Got:\n%s\n", expect, str)
+	}
+}
@@ -0,0 +1,77 @@
+package lime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Typed returns a Command which parses its args into a T, then invokes run with it.
+// If T is a struct, or a pointer to one, its tags are read as with Bind to derive the Command's Arguments and Flags,
+// so they are validated and described in --help output. Fields tagged with `flag` are always populated from the
+// flags, since the flags are removed from the args given to parse. When parse is nil, T is populated entirely from its
+// tags.
+// Typed panics if parse is nil and T is not a struct with valid tags.
+func Typed[T any](keyword string, parse func(args []string) (T, error), run func(T, io.Writer) error) Command {
+	c := Command{Keyword: keyword}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+
+	var bs []binding
+	if st.Kind() == reflect.Struct {
+		var err error
+		bs, c.Arguments, c.Flags, err = bindings(st)
+		if err != nil {
+			panic(fmt.Sprintf("lime: Typed for %q: %s", keyword, err))
+		}
+	} else if parse == nil {
+		panic(fmt.Sprintf("lime: Typed for %q needs a parse func, since %s is not a struct", keyword, t))
+	}
+
+	flagBindings := make([]binding, 0, len(bs))
+	for _, b := range bs {
+		if len(b.flag) > 0 {
+			flagBindings = append(flagBindings, b)
+		}
+	}
+
+	c.ContextFunc = func(ctx context.Context, args []string, out io.Writer) error {
+		var v T
+		populated := bs
+		if parse != nil {
+			var err error
+			if v, err = parse(args); err != nil {
+				return err
+			}
+			populated = flagBindings
+		}
+
+		if parse == nil || len(populated) > 0 {
+			if err := populate(structOf(&v), populated, args, FlagsFrom(ctx)); err != nil {
+				return err
+			}
+		}
+
+		return run(v, out)
+	}
+
+	return c
+}
+
+// structOf returns the struct which v points to, through a second pointer if needed. A nil second pointer is first
+// set to a new struct.
+func structOf(v interface{}) reflect.Value {
+	rv := reflect.ValueOf(v).Elem()
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
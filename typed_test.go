@@ -0,0 +1,81 @@
+package lime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTyped(t *testing.T) {
+	type greetOptions struct {
+		Name  string `arg:"0" required:"true"`
+		Shout bool   `flag:"shout"`
+	}
+
+	greet := func(o greetOptions, out io.Writer) error {
+		greeting := fmt.Sprintf("Hello, %s!", o.Name)
+		if o.Shout {
+			greeting = strings.ToUpper(greeting)
+		}
+		_, _ = fmt.Fprint(out, greeting)
+		return nil
+	}
+
+	// Ensure a nil parse populates T from its tags
+	{
+		c := Typed[greetOptions]("greet", nil, greet)
+
+		if c.Keyword != "greet" || len(c.Arguments) != 1 || len(c.Flags) != 1 {
+			t.Errorf("the `Typed` function did not derive the command from T, got %+v", c)
+		}
+
+		sb := new(strings.Builder)
+		err := c.ContextFunc(WithFlags(context.Background(), Flags{"shout": "true"}), []string{"John"}, sb)
+
+		if err != nil || sb.String() != "HELLO, JOHN!" {
+			t.Errorf("the typed command did not run with its populated value, got %q, %v", sb.String(), err)
+		}
+	}
+
+	// Ensure a parse func is used, with flags still populated from their tags
+	{
+		parse := func(args []string) (greetOptions, error) {
+			if len(args) == 0 {
+				return greetOptions{}, errors.New("nobody to greet")
+			}
+			return greetOptions{Name: strings.Join(args, " ")}, nil
+		}
+		c := Typed("greet", parse, greet)
+
+		sb := new(strings.Builder)
+		err := c.ContextFunc(WithFlags(context.Background(), Flags{"shout": "true"}), []string{"John", "Smith"}, sb)
+
+		if err != nil || sb.String() != "HELLO, JOHN SMITH!" {
+			t.Errorf("the typed command did not use the parse func, got %q, %v", sb.String(), err)
+		}
+
+		err = c.ContextFunc(context.Background(), []string{}, sb)
+
+		if err == nil || err.Error() != "nobody to greet" {
+			t.Errorf("the typed command did not return the parse error, got %v", err)
+		}
+	}
+
+	// Ensure non-struct types work with a parse func
+	{
+		c := Typed("double", func(args []string) (int, error) { return Args(args).Int(0) }, func(n int, out io.Writer) error {
+			_, _ = fmt.Fprint(out, n*2)
+			return nil
+		})
+
+		sb := new(strings.Builder)
+		err := c.ContextFunc(context.Background(), []string{"21"}, sb)
+
+		if err != nil || sb.String() != "42" {
+			t.Errorf("the typed command did not run with its parsed value, got %q, %v", sb.String(), err)
+		}
+	}
+}